@@ -0,0 +1,143 @@
+package main
+
+import "testing"
+
+func TestPickIndicator(t *testing.T) {
+	tests := []struct {
+		name      string
+		pile      []string
+		indicator string
+		skips     int
+		ok        bool
+	}{
+		{"normal top", []string{"R01-1", "B05-2", "K07-1"}, "K07-1", 0, true},
+		{"joker on top", []string{"R01-1", "B05-2", "JOKER-1"}, "B05-2", 1, true},
+		{"two jokers on top", []string{"G13-1", "JOKER-2", "JOKER-1"}, "G13-1", 2, true},
+		{"all jokers", []string{"JOKER-1", "JOKER-2"}, "", 0, false},
+		{"empty", nil, "", 0, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			orig := append([]string(nil), tc.pile...)
+			ind, rest, skips, ok := pickIndicator(tc.pile)
+			if ind != tc.indicator || skips != tc.skips || ok != tc.ok {
+				t.Fatalf("pickIndicator(%v) = %q, %d, %v; want %q, %d, %v",
+					tc.pile, ind, skips, ok, tc.indicator, tc.skips, tc.ok)
+			}
+			// gösterge destesinden çıkar, sahte okeyler desteye geri döner
+			want := len(tc.pile)
+			if ok {
+				want--
+			}
+			if len(rest) != want {
+				t.Fatalf("rest has %d tiles, want %d", len(rest), want)
+			}
+			for i := range orig {
+				if tc.pile[i] != orig[i] {
+					t.Fatalf("input pile modified: %v, was %v", tc.pile, orig)
+				}
+			}
+			for i := 0; i < tc.skips; i++ {
+				if !isFakeOkeyTile(rest[i]) {
+					t.Fatalf("skipped joker not moved under the pile: %v", rest)
+				}
+			}
+		})
+	}
+}
+
+func TestCalcOkeyFromIndicator(t *testing.T) {
+	tests := map[string]string{
+		"R07-1":   "R08",
+		"K13-2":   "K01",
+		"JOKER-1": "",
+		"X05-1":   "",
+		"":        "",
+	}
+	for ind, want := range tests {
+		if got := calcOkeyFromIndicator(ind); got != want {
+			t.Errorf("calcOkeyFromIndicator(%q) = %q, want %q", ind, got, want)
+		}
+	}
+}
+
+// jokerOnTop: gösterge destesinin üstüne sahte okey koyar (taş sayısı değişmez)
+func jokerOnTop(r *Room, pile int) {
+	for p, tiles := range r.Piles {
+		for i, id := range tiles {
+			if id != "JOKER-1" {
+				continue
+			}
+			top := len(r.Piles[pile]) - 1
+			r.Piles[p][i], r.Piles[pile][top] = r.Piles[pile][top], r.Piles[p][i]
+			return
+		}
+	}
+}
+
+func newDiceRoom(t *testing.T, fake FakeIndicatorRule) *Room {
+	t.Helper()
+	r, err := NewRoomManager().CreateRoom("u1", RoomConfig{FakeIndicator: fake})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.mu.Lock()
+	r.startBuildPilesLocked()
+	r.DiceValue = 5
+	r.State = "DICE_ROLL"
+	r.mu.Unlock()
+	return r
+}
+
+func pileTileCount(r *Room) int {
+	n := 0
+	for _, tiles := range r.Piles {
+		n += len(tiles)
+	}
+	return n
+}
+
+func TestApplyDiceFakeIndicatorRedeal(t *testing.T) {
+	r := newDiceRoom(t, FakeIndicatorRedeal)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// zar 5 -> gösterge destesi 2
+	jokerOnTop(r, wrapPile(r.DiceValue-3))
+
+	r.applyDiceAndPrepareDealLocked()
+
+	if r.IndicatorRedeals != 1 {
+		t.Fatalf("IndicatorRedeals = %d, want 1", r.IndicatorRedeals)
+	}
+	if r.State != "BUILD_PILES" {
+		t.Fatalf("State = %q, want BUILD_PILES", r.State)
+	}
+	if r.Indicator != "" || r.OkeyTileID != "" || r.DiceValue != 0 {
+		t.Fatalf("indicator/dice not reset: %q %q %d", r.Indicator, r.OkeyTileID, r.DiceValue)
+	}
+	if n := pileTileCount(r); n != 106 {
+		t.Fatalf("piles have %d tiles after redeal, want 106", n)
+	}
+}
+
+func TestApplyDiceFakeIndicatorNextTile(t *testing.T) {
+	r := newDiceRoom(t, FakeIndicatorNextTile)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	jokerOnTop(r, wrapPile(r.DiceValue-3))
+
+	r.applyDiceAndPrepareDealLocked()
+
+	if r.IndicatorRedeals != 0 || r.IndicatorSkips < 1 {
+		t.Fatalf("redeals=%d skips=%d, want 0 and >=1", r.IndicatorRedeals, r.IndicatorSkips)
+	}
+	if isFakeOkeyTile(r.Indicator) || r.OkeyTileID == "" {
+		t.Fatalf("indicator %q okey %q", r.Indicator, r.OkeyTileID)
+	}
+	// gösterge dışında tüm taşlar destelerde kalır
+	if n := pileTileCount(r); n != 105 {
+		t.Fatalf("piles have %d tiles, want 105", n)
+	}
+}
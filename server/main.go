@@ -27,7 +27,38 @@ const (
 	AutoStartSeconds = 5
 	BuildPileSeconds = 15
 	DiceSeconds      = 5
-	DealSeconds      = 12
+	PilesPerSeat     = 3 // dağıtımda oyuncu başı deste (saniyede 1 deste)
+	OkeyPilesPerSeat = 2 // düz okey: 14 taş (başlayan 15)
+
+	AutoDiscardBudget = 20 * time.Millisecond // süre dolunca atılacak taşı seçme
+
+	OpenMinPoints = 101 // klasik el açma alt sınırı
+	OpenMinPairs  = 5   // çift açma alt sınırı
+
+	PairOpenMultiplier = 2 // çift açanın cezası katlanır
+
+	UnusedTakePenalty      = 101 // yandan alınıp kullanılmayan taş cezası
+	WrongOpenPenalty       = 101 // hatalı el açma
+	PlayableDiscardPenalty = 101 // işlek taşı atma
+	OkeyDiscardPenalty     = 101 // okey atma
+
+	// --- El sonu puanlama (standart değerler, oda ScoreRules ile değiştirebilir)
+	NotOpenedPenalty     = 202 // eli açmayan
+	WinnerBonus          = 101 // bitiren (eksi yazılır)
+	OkeyHeldPenalty      = 101 // açan oyuncunun elinde kalan okey
+	OkeyFinishMultiplier = 2   // okey atarak bitirme
+	HandFinishMultiplier = 2   // elden bitme
+	PairFinishMultiplier = 2   // çiftten bitme
+
+	KatlamaMultiplier = 2 // katlamalı: her katlamada el puanları katlanır
+
+	IndicatorBonus   = 101 // göstergeyi gösteren (eksi yazılır)
+	IndicatorPenalty = 101 // gösterge gösterilince diğerlerine
+
+	// --- Düz okey
+	OkeyHandSize        = 14 // bitişte elde kalan taş
+	OkeyLosePoints      = 2  // bitiremeyen her oyuncuya
+	OkeyIndicatorPoints = 1  // gösterge gösterilince diğerlerine
 )
 
 type InMsg struct {
@@ -65,6 +96,7 @@ type GameMode string
 const (
     GameModeClassic  GameMode = "CLASSIC_101"
     GameModeKatlamali GameMode = "KATLAMALI_101"
+    GameModeOkey      GameMode = "OKEY" // düz okey: açma yok, elde perlerle biter
 )
 
 type PenaltyMode string
@@ -73,17 +105,143 @@ const (
     PenaltyOff PenaltyMode = "OFF"
 )
 
+type TeamMode string
+const (
+    TeamModeSolo TeamMode = "SOLO" // tekli
+    TeamModeTeam TeamMode = "TEAM" // takım: 1&3, 2&4 eş
+)
+
+// FakeIndicatorRule: gösterge sahte okey (JOKER) çıkarsa ne olur
+type FakeIndicatorRule string
+const (
+    FakeIndicatorNextTile FakeIndicatorRule = "NEXT_TILE" // JOKER destenin altına, sıradaki taş gösterge
+    FakeIndicatorRedeal   FakeIndicatorRule = "REDEAL"    // taşlar yeniden karılır, el baştan
+)
+
+// EndCondition: maçın ne zaman biteceği
+type EndCondition string
+const (
+    EndByHands  EndCondition = "HANDS"  // handCount el oynanınca
+    EndByPoints EndCondition = "POINTS" // biri pointLimit cezayı geçince
+    EndByTime   EndCondition = "TIME"   // timeLimitMin dolunca, oynanan el bitince
+)
+
+// PlayDirection: oyun yönü (sıra, dağıtım, deste sahipliği, dealer)
+type PlayDirection string
+const (
+    DirectionCW  PlayDirection = "CW"  // saat yönü: 1 -> 2 -> 3 -> 4
+    DirectionCCW PlayDirection = "CCW" // saat yönü tersi: 1 -> 4 -> 3 -> 2
+)
+
+type OpenMode string
+const (
+	OpenRun  OpenMode = "RUN"
+	OpenPair OpenMode = "PAIR"
+)
+
 type RoomConfig struct {
     GameMode    GameMode    `json:"gameMode"`
     PenaltyMode PenaltyMode `json:"penaltyMode"`
+    TeamMode    TeamMode    `json:"teamMode"`
     HandCount   int         `json:"handCount"` // 1..11
+
+    EndCondition EndCondition `json:"endCondition"`
+    PointLimit   int          `json:"pointLimit,omitempty"`   // POINTS: 101..9999
+    TimeLimitMin int          `json:"timeLimitMin,omitempty"` // TIME: 5..240 dk
+
+    FakeIndicator FakeIndicatorRule `json:"fakeIndicator"`
+    Direction     PlayDirection     `json:"direction"`
+    Seats         int               `json:"seats"` // 2..4 oyuncu
+
+    Scoring ScoreRules `json:"scoring"` // ev kuralı puan tablosu
 }
 
-type DiscardEvent struct {
-    TileID string `json:"tileId"`
+// RoomConfigInput: ROOM_CREATE config'i; scoring'de gönderilmeyen alan ile 0 ayrılır
+type RoomConfigInput struct {
+    RoomConfig
+    Scoring *ScoreRulesInput `json:"scoring,omitempty"`
+}
+
+// TableEvent: masadaki perlerle ilgili herkese görünen olaylar
+type TableEvent struct {
+    Kind       string `json:"kind"` // OKEY_SWAP
+    Seat       int    `json:"seat"`
+    TargetSeat int    `json:"targetSeat"`
+    MeldIdx    int    `json:"meldIdx"`
+    TileID     string `json:"tileId"`
+    OkeyID     string `json:"okeyId,omitempty"`
+    At         int64  `json:"at"`
+}
+
+// ceza sebep kodları (client gösterir)
+const (
+    PenaltyUnusedTake      = "UNUSED_DISCARD_TAKE"
+    PenaltyWrongOpen       = "WRONG_OPEN"
+    PenaltyPlayableDiscard = "PLAYABLE_DISCARD"
+    PenaltyOkeyDiscard     = "OKEY_DISCARD"
+)
+
+// PenaltyEvent: el içinde yazılan ceza (skor tablosuna eklenir)
+type PenaltyEvent struct {
     Seat   int    `json:"seat"`
     UserID string `json:"userId"`
-    At     int64  `json:"at"` // unix seconds (opsiyonel ama iyi)
+    Points int    `json:"points"`
+    Reason string `json:"reason"` // Penalty* sebep kodu
+    At     int64  `json:"at"`
+}
+
+// HandResult: el sonu (HAND_RESULT), INTERMISSION'dan önce yayınlanır
+type HandResult struct {
+    HandIndex    int              `json:"handIndex"`  // biten elin numarası (1..)
+    WinnerSeat   int              `json:"winnerSeat"` // 0 = kazanan yok
+    WinnerUserID string           `json:"winnerUserId,omitempty"`
+    Reason       string           `json:"reason"` // FINISH | DRAW_PILE_EMPTY
+    LastTile     string           `json:"lastTile,omitempty"`
+    OkeyFinish   bool             `json:"okeyFinish"` // son taş okey atılarak bitti
+    HandFinish   bool             `json:"handFinish"` // elden bitti (önceden açmadan)
+    PairFinish   bool             `json:"pairFinish"` // çiftten bitti
+    Hands        map[int][]string `json:"hands"`      // kalan taşlar (açık)
+    Scores       map[int]int      `json:"scores"`     // bu elin puanları
+    Totals       map[int]int      `json:"totals"`     // maç toplamı
+    At           int64            `json:"at"`
+}
+
+// GameResult: maç sonu (GAME_RESULT)
+type GameResult struct {
+    Standings  []Standing     `json:"standings"`
+    Scoreboard []HandScoreRow `json:"scoreboard"`
+    Totals     map[int]int    `json:"totals"`
+    TeamTotals map[int]int    `json:"teamTotals,omitempty"`
+    HandCount  int            `json:"handCount"` // oynanan el
+    EndReason  EndCondition   `json:"endReason"`
+    At         int64          `json:"at"`
+}
+
+// MatchProgress: bitiş koşuluna ne kadar kaldı (client gösterir)
+type MatchProgress struct {
+    EndCondition EndCondition `json:"endCondition"`
+    HandsPlayed  int          `json:"handsPlayed"`
+    HandCount    int          `json:"handCount,omitempty"`    // HANDS
+    PointLimit   int          `json:"pointLimit,omitempty"`   // POINTS
+    MaxTotal     int          `json:"maxTotal"`               // en yüksek ceza toplamı
+    StartedAt    int64        `json:"startedAt,omitempty"`    // unix ts
+    EndsAt       int64        `json:"endsAt,omitempty"`       // TIME: bu andan sonra biten el son el
+}
+
+// HandScoreRow: skor tablosunda bir el satırı
+type HandScoreRow struct {
+    HandIndex  int         `json:"handIndex"`
+    WinnerSeat int         `json:"winnerSeat"`
+    Scores     map[int]int `json:"scores"`               // seat -> puan (ceza +, bonus -)
+    TeamScores map[int]int `json:"teamScores,omitempty"` // takım -> puan (takım modunda)
+}
+
+type DiscardEvent struct {
+    TileID  string `json:"tileId"`
+    Seat    int    `json:"seat"`
+    UserID  string `json:"userId"`
+    At      int64  `json:"at"` // unix seconds (opsiyonel ama iyi)
+    TakenBy int    `json:"takenBy,omitempty"` // yandan alan seat (geçmişte)
 }
 
 
@@ -102,6 +260,16 @@ type Room struct {
 	// --- Hand loop
 	HandIndex int `json:"handIndex"` // 0.. (completed hands)
 
+	// --- Skor tablosu (maç boyunca)
+	Scoreboard  []HandScoreRow `json:"scoreboard"`
+	TotalScores map[int]int    `json:"totalScores"`
+	TeamTotals  map[int]int    `json:"teamTotals"` // takım modunda
+	Standings   []Standing     `json:"standings"`  // maç bitince
+
+	LastGameResult *GameResult `json:"lastGameResult"`
+
+	MatchStartedAt int64 `json:"matchStartedAt"` // unix ts, ilk el başlangıcı
+
 	// turn timer anti double-fire
 	turnTimerGen int64 `json:"-"`
 	// score / intermission
@@ -134,6 +302,10 @@ type Room struct {
 	Indicator     string `json:"indicator"`
 	OkeyTileID    string `json:"okey"`
 
+	// sahte okey gösterge: kaç kez sıradaki taşa geçildi / yeniden dağıtıldı
+	IndicatorSkips   int `json:"indicatorSkips"`
+	IndicatorRedeals int `json:"indicatorRedeals"`
+
 	// --- Piles
 	Piles     map[int][]string `json:"-"` // 1..15 each 7 tiles
 	ExtraTile string           `json:"-"` // 106. taş
@@ -157,9 +329,40 @@ type Room struct {
 
 	// taş state
 	DrawPile []string `json:"-"` // draw stack gerçek taş listesi (server)
-	Discards []DiscardEvent `json:"-"`
+	Discards []DiscardEvent `json:"-"` // tüm atılanlar (geçmiş, silinmez)
+	DiscardStacks map[int][]DiscardEvent `json:"-"` // seat -> kendi atık destesi (üst = son)
 	Hands    map[int][]string `json:"-"`
 
+	// --- El açma (masadaki perler, sadece sahibinin alanında)
+	OpenedMelds  map[int][]TableMeld `json:"openedMelds"`  // seat -> açılan perler
+	OpenedPoints map[int]int         `json:"openedPoints"` // seat -> açılış toplamı (çiftte çift sayısı)
+	PairOpeners  map[int]bool        `json:"pairOpeners"`  // seat -> çift açtı mı?
+
+	// --- Açma eşiği (klasikte sabit, katlamalıda her açılışta yükselir)
+	RunThreshold  int `json:"runThreshold"`  // seri açmak için gereken en az puan
+	PairThreshold int `json:"pairThreshold"` // çift açmak için gereken en az çift
+	KatlamaLevel  int `json:"katlamaLevel"`  // katlama sayısı (ilk açılış hariç)
+	TableEvents  []TableEvent        `json:"tableEvents"`  // okey alma vs (el boyunca)
+	Penalties    []PenaltyEvent      `json:"penalties"`    // el içi cezalar
+
+	// --- Yandan alma: bu turda kullanılması zorunlu taş
+	TakenDiscard     string       `json:"takenDiscard"`
+	TakenDiscardSeat int          `json:"takenDiscardSeat"`
+	takenEvent       DiscardEvent `json:"-"` // geri koymak için
+	takenHistIdx     int          `json:"-"` // Discards içindeki kaydı
+	takeReverted     bool         `json:"-"` // bu turda alınan taş geri kondu: yandan alma yok
+
+	// --- El bitişi
+	LastHandResult *HandResult  `json:"lastHandResult"`
+	turnNo         int          `json:"-"` // el içindeki tur sayacı
+	openedTurn     map[int]int  `json:"-"` // seat -> açtığı tur (elden bitme için)
+	finishSeat     int          `json:"-"`
+	finishTile     string       `json:"-"`
+
+	// --- Gösterge
+	IndicatorShownBy int          `json:"indicatorShownBy"` // 0 = gösterilmedi
+	discardedSeats   map[int]bool `json:"-"`                // ilk taşını attı mı?
+
 	// internal
 	mu    sync.RWMutex     `json:"-"`
 	conns map[string]*Conn `json:"-"`
@@ -179,6 +382,14 @@ type RoomSnapshot struct {
 
 	HandIndex int `json:"handIndex"`
 
+	Scoreboard  []HandScoreRow `json:"scoreboard"`
+	TotalScores map[int]int    `json:"totalScores"`
+	TeamTotals  map[int]int    `json:"teamTotals,omitempty"`
+	Standings   []Standing     `json:"standings,omitempty"`
+
+	LastGameResult *GameResult `json:"lastGameResult,omitempty"`
+	MatchProgress  MatchProgress `json:"matchProgress"`
+
 	AutoStartLeft int `json:"autoStartLeft"`
 
 	BuildPileIdx int `json:"buildPileIdx"`
@@ -191,6 +402,9 @@ type RoomSnapshot struct {
 	Indicator     string `json:"indicator"`
 	Okey          string `json:"okey"`
 
+	IndicatorSkips   int `json:"indicatorSkips"`
+	IndicatorRedeals int `json:"indicatorRedeals"`
+
 	PileOwners  map[int]int `json:"pileOwners"`
 	PileCounts  map[int]int `json:"pileCounts"`
 	DrawPileIds []int       `json:"drawPileIds"`
@@ -205,8 +419,27 @@ type RoomSnapshot struct {
 
 	DrawCount  int         `json:"drawCount"`
 	Discards []DiscardEvent `json:"discards"`
+	DiscardTops   map[int]string `json:"discardTops"`   // seat -> atık destesinin üstü
+	DiscardCounts map[int]int    `json:"discardCounts"` // seat -> atık destesi sayısı
 	HandCounts map[int]int `json:"handCounts"`
 	MyHand []string    `json:"myHand"`
+
+	OpenedMelds  map[int][]TableMeld `json:"openedMelds"`
+	OpenedPoints map[int]int         `json:"openedPoints"`
+	PairOpeners  map[int]bool        `json:"pairOpeners"`
+	TableEvents  []TableEvent        `json:"tableEvents"`
+
+	RunThreshold  int `json:"runThreshold"`
+	PairThreshold int `json:"pairThreshold"`
+	KatlamaLevel  int `json:"katlamaLevel"`
+	Penalties    []PenaltyEvent      `json:"penalties"`
+
+	TakenDiscard     string `json:"takenDiscard"`
+	TakenDiscardSeat int    `json:"takenDiscardSeat"`
+
+	LastHandResult *HandResult `json:"lastHandResult,omitempty"`
+
+	IndicatorShownBy int `json:"indicatorShownBy"`
 }
 
 
@@ -396,11 +629,57 @@ func wrapSeat(s int) int {
 }
 // func nextSeat(s int) int { return wrapSeat(s + 1) }
 
-func nextSeat(seat int) int {
+func nextSeat(seat, n int) int {
 	seat++
-	if seat > 4 {
+	if seat > n {
+		return 1
+	}
+	return seat
+}
+
+// seatCount: masadaki oyuncu sayısı (2..4)
+func (r *Room) seatCount() int {
+	if r.Config.Seats < 2 || r.Config.Seats > 4 {
+		return 4
+	}
+	return r.Config.Seats
+}
+
+// dealPiles: dağıtılan deste sayısı (kalanlar çekme destesi)
+func (r *Room) dealPiles() int {
+	return r.rules().DealLayout().PilesPerSeat * r.seatCount()
+}
+
+// seatAfter: oyun yönünde sıradaki oyuncu (sağdaki)
+func (r *Room) seatAfter(seat int) int {
+	if r.Config.Direction == DirectionCCW {
+		return prevSeat(seat, r.seatCount())
+	}
+	return nextSeat(seat, r.seatCount())
+}
+
+// seatBefore: oyun yönünde önceki oyuncu (soldaki, atığını alabildiğimiz)
+func (r *Room) seatBefore(seat int) int {
+	if r.Config.Direction == DirectionCCW {
+		return nextSeat(seat, r.seatCount())
+	}
+	return prevSeat(seat, r.seatCount())
+}
+
+// teamOf: takım modunda 1&3 -> takım 1, 2&4 -> takım 2
+func teamOf(seat int) int {
+	if seat%2 == 1 {
 		return 1
 	}
+	return 2
+}
+
+// prevSeat: seat-1 (saat yönünde soldaki oyuncu)
+func prevSeat(seat, n int) int {
+	seat--
+	if seat < 1 {
+		return n
+	}
 	return seat
 }
 
@@ -431,11 +710,38 @@ func calcOkeyFromIndicator(ind string) string {
 	numStr := ind[1:3]
 	n := 0
 	_, _ = fmt.Sscanf(numStr, "%d", &n)
+	// JOKER vs geçersiz gösterge -> okey yok
+	if !strings.Contains("RBGK", color) || n < 1 || n > 13 { return "" }
 	n++
 	if n > 13 { n = 1 }
 	return fmt.Sprintf("%s%02d", color, n)
 }
 
+func isFakeOkeyTile(id string) bool {
+	return strings.HasPrefix(id, "JOKER")
+}
+
+func topTile(pile []string) string {
+	if len(pile) == 0 { return "" }
+	return pile[len(pile)-1]
+}
+
+// pickIndicator: destenin üst taşı gösterge olur. Sahte okey çıkarsa
+// destenin altına konur ve sıradaki taşa geçilir (taş sayısı korunur).
+func pickIndicator(pile []string) (indicator string, rest []string, skips int, ok bool) {
+	rest = append([]string(nil), pile...)
+	for tries := 0; tries < len(pile); tries++ {
+		top := rest[len(rest)-1]
+		rest = rest[:len(rest)-1]
+		if !isFakeOkeyTile(top) {
+			return top, rest, skips, true
+		}
+		rest = append([]string{top}, rest...)
+		skips++
+	}
+	return "", pile, 0, false
+}
+
 func genRoomID(n int) (string, error) {
 	const alphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
 	out := make([]byte, n)
@@ -499,6 +805,12 @@ func (r *Room) snapshotForUser(userID string) RoomSnapshot {
 
 	discards := make([]DiscardEvent, len(r.Discards))
 	copy(discards, r.Discards)
+	discardTops := make(map[int]string, len(r.DiscardStacks))
+	discardCounts := make(map[int]int, len(r.DiscardStacks))
+	for seat, st := range r.DiscardStacks {
+		discardCounts[seat] = len(st)
+		if len(st) > 0 { discardTops[seat] = st[len(st)-1].TileID }
+	}
 
 
 	po := make(map[int]int, 15)
@@ -508,6 +820,36 @@ func (r *Room) snapshotForUser(userID string) RoomSnapshot {
 	dp := make([]int, len(r.DrawPileIds))
 	copy(dp, r.DrawPileIds)
 
+	opened := make(map[int][]TableMeld, len(r.OpenedMelds))
+	for seat, ms := range r.OpenedMelds {
+		cp := make([]TableMeld, len(ms))
+		for i, m := range ms {
+			cp[i] = m
+			cp[i].Tiles = append([]string(nil), m.Tiles...)
+		}
+		opened[seat] = cp
+	}
+	scoreboard := make([]HandScoreRow, len(r.Scoreboard))
+	copy(scoreboard, r.Scoreboard) // satırlar yazıldıktan sonra değişmez
+	totals := make(map[int]int, len(r.TotalScores))
+	for k, v := range r.TotalScores { totals[k] = v }
+	var teamTotals map[int]int
+	if r.TeamTotals != nil {
+		teamTotals = make(map[int]int, len(r.TeamTotals))
+		for k, v := range r.TeamTotals { teamTotals[k] = v }
+	}
+	standings := make([]Standing, len(r.Standings))
+	copy(standings, r.Standings)
+
+	openedPts := make(map[int]int, len(r.OpenedPoints))
+	for k, v := range r.OpenedPoints { openedPts[k] = v }
+	pairOpeners := make(map[int]bool, len(r.PairOpeners))
+	for k, v := range r.PairOpeners { pairOpeners[k] = v }
+	tableEvents := make([]TableEvent, len(r.TableEvents))
+	copy(tableEvents, r.TableEvents)
+	penalties := make([]PenaltyEvent, len(r.Penalties))
+	copy(penalties, r.Penalties)
+
 	return RoomSnapshot{
 		RoomID: r.ID, State: r.State, OwnerID: r.OwnerID, Updated: r.Updated,
 		Players: players,
@@ -520,6 +862,12 @@ func (r *Room) snapshotForUser(userID string) RoomSnapshot {
 		Config:       r.Config,
 		ConfigLocked: r.ConfigLocked,
 		HandIndex: r.HandIndex,
+		Scoreboard: scoreboard,
+		TotalScores: totals,
+		TeamTotals: teamTotals,
+		Standings: standings,
+		LastGameResult: r.LastGameResult,
+		MatchProgress: r.matchProgressLocked(),
 
 
 		DiceLeft: r.DiceLeft,
@@ -530,6 +878,9 @@ func (r *Room) snapshotForUser(userID string) RoomSnapshot {
 		Indicator: r.Indicator,
 		Okey: r.OkeyTileID,
 
+		IndicatorSkips: r.IndicatorSkips,
+		IndicatorRedeals: r.IndicatorRedeals,
+
 		PileOwners: po,
 		PileCounts: pc,
 		DrawPileIds: dp,
@@ -544,8 +895,39 @@ func (r *Room) snapshotForUser(userID string) RoomSnapshot {
 
 		DrawCount: len(r.DrawPile),
 		Discards: discards,
+		DiscardTops: discardTops,
+		DiscardCounts: discardCounts,
 		HandCounts: handCounts,
 		MyHand: myHand,
+
+		OpenedMelds: opened,
+		OpenedPoints: openedPts,
+		PairOpeners: pairOpeners,
+		TableEvents: tableEvents,
+		Penalties: penalties,
+
+		RunThreshold: r.RunThreshold,
+		PairThreshold: r.PairThreshold,
+		KatlamaLevel: r.KatlamaLevel,
+
+		TakenDiscard: r.TakenDiscard,
+		TakenDiscardSeat: r.TakenDiscardSeat,
+
+		LastHandResult: r.LastHandResult,
+
+		IndicatorShownBy: r.IndicatorShownBy,
+	}
+}
+
+// broadcastLocked: r.mu altında mesajı odadaki herkese kuyruğa koyar.
+// Sonraki snapshot'lardan önce gitmesi garanti olur.
+func (r *Room) broadcastLocked(out OutMsg) {
+	b, _ := json.Marshal(out)
+	for _, c := range r.conns {
+		select {
+		case c.send <- b:
+		default:
+		}
 	}
 }
 
@@ -585,9 +967,9 @@ func (r *Room) detachConn(userID string) {
 	}
 	r.Updated = time.Now().Unix()
 
-	// autoStart iptali (4 değilse)
-	if r.State == "AUTO_START" || r.State == "LOBBY" {
-		if len(r.Players) != 4 {
+	// autoStart iptali (bağlı oyuncu eksikse)
+	if r.State == "AUTO_START" || r.State == "LOBBY" || r.State == "FINISHED" {
+		if r.connectedCountLocked() != r.seatCount() {
 			r.stopAutoStartLocked()
 			r.State = "LOBBY"
 		}
@@ -595,6 +977,17 @@ func (r *Room) detachConn(userID string) {
 	r.mu.Unlock()
 }
 
+// connectedCountLocked: masadaki bağlı oyuncu sayısı (kopan oyuncu Players'ta kalır)
+func (r *Room) connectedCountLocked() int {
+	n := 0
+	for _, p := range r.Players {
+		if p.Connected {
+			n++
+		}
+	}
+	return n
+}
+
 /* =========================
    Join + AutoStart
    ========================= */
@@ -608,10 +1001,11 @@ func (r *Room) join(userID string) (int, error) {
 		if p.UserID == userID {
 			p.Connected = true
 			r.Updated = time.Now().Unix()
+			r.tryAutoStartLocked()
 			return s, nil
 		}
 	}
-	for seat := 1; seat <= 4; seat++ {
+	for seat := 1; seat <= r.seatCount(); seat++ {
 		if _, exists := r.Players[seat]; !exists {
 			r.Players[seat] = &Player{UserID: userID, Seat: seat, Connected: true}
 			r.Updated = time.Now().Unix()
@@ -631,20 +1025,23 @@ func (r *Room) stopAutoStartLocked() {
 }
 
 func (r *Room) tryAutoStartLocked() {
-	if r.State != "LOBBY" && r.State != "AUTO_START" {
+	if r.State != "LOBBY" && r.State != "AUTO_START" && r.State != "FINISHED" {
 		return
 	}
-	if len(r.Players) != 4 {
+	if r.connectedCountLocked() != r.seatCount() {
 		r.stopAutoStartLocked()
 		r.State = "LOBBY"
 		return
 	}
 	// zaten çalışıyorsa
-	if r.State == "AUTO_START" && r.autoStartTimer != nil {
+	if r.autoStartTimer != nil {
 		return
 	}
 
-	r.State = "AUTO_START"
+	// maç sonu: geri sayım boyunca FINISHED (sonuç ekranı) görünür kalır
+	if r.State != "FINISHED" {
+		r.State = "AUTO_START"
+	}
 	r.AutoStartLeft = AutoStartSeconds
 	r.Updated = time.Now().Unix()
 
@@ -658,7 +1055,8 @@ func (r *Room) tryAutoStartLocked() {
 func (r *Room) onAutoStartTick() {
 	r.mu.Lock()
 	// koşullar bozulduysa iptal
-	if len(r.Players) != 4 || (r.State != "AUTO_START" && r.State != "LOBBY") {
+	if r.connectedCountLocked() != r.seatCount() ||
+		(r.State != "AUTO_START" && r.State != "LOBBY" && r.State != "FINISHED") {
 		r.stopAutoStartLocked()
 		r.State = "LOBBY"
 		r.Updated = time.Now().Unix()
@@ -690,6 +1088,11 @@ func (r *Room) onAutoStartTick() {
 	go r.broadcastSnapshot()
 
 	r.mu.Lock()
+	// arada iptal edildiyse tekrar kurma
+	if r.autoStartTimer == nil {
+		r.mu.Unlock()
+		return
+	}
 	// tekrar kur
 	r.autoStartTimer = time.AfterFunc(1*time.Second, func() {
 		r.onAutoStartTick()
@@ -728,11 +1131,25 @@ func (r *Room) startBuildPilesLocked() {
 	r.State = "BUILD_PILES"
 	r.BuildPileIdx = 1
 
+	// yeni maç: skor tablosu sıfır
+	if r.HandIndex == 0 {
+		r.Scoreboard = nil
+		r.TotalScores = make(map[int]int, 4)
+		r.TeamTotals = nil
+		if r.Config.TeamMode == TeamModeTeam {
+			r.TeamTotals = make(map[int]int, 2)
+		}
+		r.Standings = nil
+		r.MatchStartedAt = time.Now().Unix()
+	}
+
 	// reset game state
 	r.Hands = make(map[int][]string, 4)
 	r.Discards = nil
+	r.DiscardStacks = make(map[int][]DiscardEvent, 4)
 	r.DrawPile = nil
 	r.DrawPileIds = nil
+	r.resetTableLocked()
 
 	r.StartPile = 0
 	r.IndicatorPile = 0
@@ -825,26 +1242,18 @@ func (r *Room) startBuildPilesLocked() {
 }
 
 func (r *Room) recalcPileOwnersLocked() {
-	// dealerSeat -> piles 1-4
-	// dealer+1 -> 5-8
-	// dealer+2 -> 9-12
-	// dealer+3 -> 13-15
-	d := r.DealerSeat
-	s2 := nextSeat(d)
-	s3 := nextSeat(s2)
-	s4 := nextSeat(s3)
+	// 4 kişi: dealer 1-4, dealer+1 5-8, dealer+2 9-12, dealer+3 13-15
+	// 3 kişi: 5'er deste; 2 kişi: 8 + 7
+	// (+N: oyun yönünde N sonraki)
+	n := r.seatCount()
+	per := (15 + n - 1) / n
 
+	seat := r.DealerSeat
 	for p := 1; p <= 15; p++ {
-		switch {
-		case p >= 1 && p <= 4:
-			r.PileOwners[p] = d
-		case p >= 5 && p <= 8:
-			r.PileOwners[p] = s2
-		case p >= 9 && p <= 12:
-			r.PileOwners[p] = s3
-		case p >= 13 && p <= 15:
-			r.PileOwners[p] = s4
+		if p > 1 && (p-1)%per == 0 {
+			seat = r.seatAfter(seat)
 		}
+		r.PileOwners[p] = seat
 	}
 }
 
@@ -948,11 +1357,22 @@ func (r *Room) applyDiceAndPrepareDealLocked() {
 	r.IndicatorPile = wrapPile(r.StartPile - 3)
 
 	// indicator = indicatorPile üst taşı (pile içinden çıkar)
-	pile := r.Piles[r.IndicatorPile]
-	if len(pile) > 0 {
-		r.Indicator = pile[len(pile)-1]
-		pile = pile[:len(pile)-1]
-		r.Piles[r.IndicatorPile] = pile
+	// sahte okey çıkarsa: ev kuralına göre sıradaki taş ya da yeniden dağıtım
+	r.IndicatorSkips = 0
+	if r.Config.FakeIndicator == FakeIndicatorRedeal && isFakeOkeyTile(topTile(r.Piles[r.IndicatorPile])) {
+		r.IndicatorRedeals++
+		r.DiceLeft = 0
+		r.DiceValue = 0
+		r.diceStopBy = ""
+		r.startBuildPilesLocked()
+		go r.broadcastSnapshot()
+		return
+	}
+	ind, rest, skips, ok := pickIndicator(r.Piles[r.IndicatorPile])
+	if ok {
+		r.Indicator = ind
+		r.Piles[r.IndicatorPile] = rest
+		r.IndicatorSkips = skips
 	}
 	r.OkeyTileID = calcOkeyFromIndicator(r.Indicator)
 
@@ -990,9 +1410,9 @@ func (r *Room) applyDiceAndPrepareDealLocked() {
 
 func (r *Room) startDealingLocked() {
 	r.State = "DEALING"
-	r.DealLeft = DealSeconds
+	r.DealLeft = r.dealPiles()
 	r.DealCursor = r.StartPile
-	r.DealSeatCursor = nextSeat(r.DealerSeat)
+	r.DealSeatCursor = r.seatAfter(r.DealerSeat)
 
 	r.Updated = time.Now().Unix()
 
@@ -1040,19 +1460,19 @@ func (r *Room) dealOnePileLocked() {
 	// advance
 	r.DealLeft--
 	r.DealCursor = wrapPile(r.DealCursor + 1)
-	r.DealSeatCursor = nextSeat(r.DealSeatCursor)
+	r.DealSeatCursor = r.seatAfter(r.DealSeatCursor)
 }
 
 func (r *Room) finalizeAfterDealLocked() {
-	// kalan 3 pile => draw stack (order preserved)
-	// dağıtılan 12 pile startPile'dan itibaren 12 adettir
+	// kalan pile'lar => draw stack (order preserved)
+	// dağıtılan 3*oyuncu pile startPile'dan itibaren (4 kişide 12, kalan 3)
 	dealt := make(map[int]bool, 15)
 	cursor := r.StartPile
-	for i := 0; i < DealSeconds; i++ {
+	for i := 0; i < r.dealPiles(); i++ {
 		dealt[wrapPile(cursor)] = true
 		cursor++
 	}
-	remainIds := make([]int, 0, 3)
+	remainIds := make([]int, 0, 15-r.dealPiles())
 	for p := 1; p <= 15; p++ {
 		if !dealt[p] {
 			remainIds = append(remainIds, p)
@@ -1074,9 +1494,10 @@ func (r *Room) finalizeAfterDealLocked() {
 	r.State = "PLAYING"
 
 	// ✅ Oyun dealer’ın üstünden başlar (22 taş onda)
-	// dealerSeat=1 ise turnSeat=2
-	r.TurnSeat = nextSeat(r.DealerSeat)
+	// dealerSeat=1 ise turnSeat=2 (CCW: 4)
+	r.TurnSeat = r.seatAfter(r.DealerSeat)
 	r.TurnPhase = "WAIT_DISCARD"
+	r.turnNo = 1
 
 	r.Updated = time.Now().Unix()
 
@@ -1177,15 +1598,21 @@ func (r *Room) endHandLocked() {
 	// hand tamamlandı
 	r.HandIndex++
 
+	// ✅ el sonucu: INTERMISSION'dan önce herkese
+	res := r.buildHandResultLocked()
+	r.scoreHandLocked(&res)
+	r.LastHandResult = &res
+	r.broadcastLocked(OutMsg{T: "HAND_RESULT", P: res})
+
 	// oyun bitti mi?
-	if r.Config.HandCount > 0 && r.HandIndex >= r.Config.HandCount {
-		r.State = "FINISHED" // veya "GAME_OVER"
-		r.Updated = time.Now().Unix()
-		return
+	gameOver := r.matchOverLocked()
+	if gameOver {
+		r.State = "FINISHED" // geri sayım bitene kadar görünür
+		r.finishMatchLocked()
 	}
 
 	// yeni el: dealer +1
-	r.DealerSeat = nextSeat(r.DealerSeat)
+	r.DealerSeat = r.seatAfter(r.DealerSeat)
 
 	// yeni el state sıfırla (startBuildPilesLocked zaten çoğunu resetliyor ama net olsun)
 	r.AutoStartLeft = 0
@@ -1204,15 +1631,27 @@ func (r *Room) endHandLocked() {
 
 	r.BuildPileIdx = 0
 	r.Discards = nil
+	r.DiscardStacks = make(map[int][]DiscardEvent, 4)
 	r.DrawPile = nil
 	r.DrawPileIds = nil
 	r.Hands = make(map[int][]string, 4)
+	r.resetTableLocked()
+	r.IndicatorSkips = 0
+	r.IndicatorRedeals = 0
 
 	// pileCounts reset
 	for i := 1; i <= 15; i++ {
 		r.PileCounts[i] = 0
 	}
 
+	if gameOver {
+		// ✅ P: masa doluysa aynı ayarlarla yeni maç (5 sn geri sayım)
+		r.HandIndex = 0
+		r.tryAutoStartLocked()
+		r.Updated = time.Now().Unix()
+		return
+	}
+
 	// direkt yeni el akışına gir
 	// skor arası başlat (10 sn)
 	r.IntermissionUntil = time.Now().Add(10 * time.Second).Unix()
@@ -1228,6 +1667,271 @@ func (r *Room) endHandLocked() {
 
 
 
+// finishHandLocked: açmış oyuncu son taşını işledi / attı -> el biter
+func (r *Room) finishHandLocked(seat int, lastTile string) {
+	r.finishSeat = seat
+	r.finishTile = lastTile
+	r.endHandLocked()
+}
+
+func (r *Room) buildHandResultLocked() HandResult {
+	res := HandResult{
+		HandIndex: r.HandIndex,
+		Reason:    "DRAW_PILE_EMPTY",
+		Hands:     make(map[int][]string, len(r.Players)),
+		At:        time.Now().Unix(),
+	}
+	for seat := range r.Players {
+		res.Hands[seat] = append([]string{}, r.Hands[seat]...)
+	}
+
+	seat := r.finishSeat
+	if seat == 0 {
+		return res
+	}
+	res.Reason = "FINISH"
+	res.WinnerSeat = seat
+	if p, ok := r.Players[seat]; ok && p != nil {
+		res.WinnerUserID = p.UserID
+	}
+	res.LastTile = r.finishTile
+	// okey atarak bitirme (işlekle bitirmede sayılmaz)
+	if r.finishTile != "" && parseTile(r.finishTile, "", r.OkeyTileID).IsRealOkey {
+		res.OkeyFinish = true
+	}
+	// elden bitme: bu turdan önce açmamıştı (açma olmayan varyantta sayılmaz)
+	rules := r.rules()
+	res.HandFinish = rules.HasTable() && r.openedTurn[seat] == r.turnNo
+	_, pairs := rules.DetectFinish(r.Hands[seat], len(r.OpenedMelds[seat]) > 0, r.OkeyTileID)
+	res.PairFinish = r.PairOpeners[seat] || pairs
+	return res
+}
+
+// finishMatchLocked: genel sıralama + sıralama puanları, GAME_RESULT yayını
+func (r *Room) finishMatchLocked() {
+	seats := make([]int, 0, len(r.Players))
+	for seat := range r.Players {
+		seats = append(seats, seat)
+	}
+	// sıralama puanındaki n = oynanan el (HANDS'te handCount'a eşit)
+	r.Standings = computeStandings(seats, r.TotalScores, r.Scoreboard, r.Config.TeamMode == TeamModeTeam, r.HandIndex)
+
+	gr := GameResult{
+		Standings:  append([]Standing(nil), r.Standings...),
+		Scoreboard: append([]HandScoreRow(nil), r.Scoreboard...),
+		Totals:     make(map[int]int, len(r.TotalScores)),
+		HandCount:  r.HandIndex,
+		EndReason:  r.Config.EndCondition,
+		At:         time.Now().Unix(),
+	}
+	for k, v := range r.TotalScores { gr.Totals[k] = v }
+	if r.TeamTotals != nil {
+		gr.TeamTotals = make(map[int]int, len(r.TeamTotals))
+		for k, v := range r.TeamTotals { gr.TeamTotals[k] = v }
+	}
+	r.LastGameResult = &gr
+	r.broadcastLocked(OutMsg{T: "GAME_RESULT", P: gr})
+}
+
+// maxTotalLocked: en yüksek ceza toplamı (takım modunda takım toplamı)
+func (r *Room) maxTotalLocked() int {
+	totals := r.TotalScores
+	if r.Config.TeamMode == TeamModeTeam && r.TeamTotals != nil {
+		totals = r.TeamTotals
+	}
+	max, first := 0, true
+	for _, v := range totals {
+		if first || v > max {
+			max, first = v, false
+		}
+	}
+	return max
+}
+
+// matchOverLocked: el bittikten sonra maç bitti mi? (bitiş koşuluna göre)
+func (r *Room) matchOverLocked() bool {
+	switch r.Config.EndCondition {
+	case EndByPoints:
+		return r.maxTotalLocked() > r.Config.PointLimit
+	case EndByTime:
+		limit := int64(r.Config.TimeLimitMin) * 60
+		return r.MatchStartedAt > 0 && time.Now().Unix()-r.MatchStartedAt >= limit
+	}
+	return r.Config.HandCount > 0 && r.HandIndex >= r.Config.HandCount
+}
+
+func (r *Room) matchProgressLocked() MatchProgress {
+	mp := MatchProgress{
+		EndCondition: r.Config.EndCondition,
+		HandsPlayed:  r.HandIndex,
+		MaxTotal:     r.maxTotalLocked(),
+		StartedAt:    r.MatchStartedAt,
+	}
+	switch r.Config.EndCondition {
+	case EndByPoints:
+		mp.PointLimit = r.Config.PointLimit
+	case EndByTime:
+		if r.MatchStartedAt > 0 {
+			mp.EndsAt = r.MatchStartedAt + int64(r.Config.TimeLimitMin)*60
+		}
+	default:
+		mp.HandCount = r.Config.HandCount
+	}
+	return mp
+}
+
+// handMultiplierLocked: katlamalıda her katlama el puanlarını katlar
+func (r *Room) handMultiplierLocked() int {
+	return r.rules().HandMultiplier(r.KatlamaLevel, r.Config.Scoring)
+}
+
+// scoreHandLocked: el puanlarını hesaplar, skor tablosuna satır ekler
+func (r *Room) scoreHandLocked(res *HandResult) {
+	seats := make([]int, 0, len(r.Players))
+	opened := make(map[int]bool, len(r.Players))
+	for seat := range r.Players {
+		seats = append(seats, seat)
+		opened[seat] = len(r.OpenedMelds[seat]) > 0
+	}
+	sort.Ints(seats)
+
+	scores := r.rules().ScoreHand(handScoreInput{
+		seats:       seats,
+		hands:       r.Hands,
+		opened:      opened,
+		pairOpeners: r.PairOpeners,
+		penalties:   r.Penalties,
+		okeyBase:    r.OkeyTileID,
+		winner:      res.WinnerSeat,
+		okeyFinish:  res.OkeyFinish,
+		handFinish:  res.HandFinish,
+		pairFinish:  res.PairFinish,
+		multiplier:  r.handMultiplierLocked(),
+		teams:       r.Config.TeamMode == TeamModeTeam,
+		indicatorBy: r.IndicatorShownBy,
+		score:       r.Config.Scoring,
+	})
+
+	if r.TotalScores == nil {
+		r.TotalScores = make(map[int]int, 4)
+	}
+	for seat, pts := range scores {
+		r.TotalScores[seat] += pts
+	}
+	row := HandScoreRow{
+		HandIndex:  res.HandIndex,
+		WinnerSeat: res.WinnerSeat,
+		Scores:     scores,
+	}
+	if r.Config.TeamMode == TeamModeTeam {
+		if r.TeamTotals == nil {
+			r.TeamTotals = make(map[int]int, 2)
+		}
+		row.TeamScores = make(map[int]int, 2)
+		for seat, pts := range scores {
+			row.TeamScores[teamOf(seat)] += pts
+			r.TeamTotals[teamOf(seat)] += pts
+		}
+	}
+	r.Scoreboard = append(r.Scoreboard, row)
+
+	res.Scores = scores
+	res.Totals = make(map[int]int, len(r.TotalScores))
+	for k, v := range r.TotalScores { res.Totals[k] = v }
+}
+
+// autoDiscardLocked: sıradaki oyuncu adına taş atar ve turu ilerletir
+// (süre dolumu / hatalı el açma). Gerekirse el biter.
+func (r *Room) autoDiscardLocked() {
+	// yandan alınan taş kullanılmadıysa: geri koy + desteden çek
+	if r.TakenDiscard != "" && r.TakenDiscardSeat == r.TurnSeat {
+		r.revertTakenDiscardLocked(r.TurnSeat)
+		if len(r.DrawPile) == 0 {
+			r.endHandLocked()
+			return
+		}
+		t := r.DrawPile[0]
+		r.DrawPile = r.DrawPile[1:]
+		r.Hands[r.TurnSeat] = append(r.Hands[r.TurnSeat], t)
+		r.TurnPhase = "WAIT_DISCARD"
+	}
+
+	hand := r.Hands[r.TurnSeat]
+	if len(hand) > 0 {
+		// ✅ gerçek DISCARD (dizilimi en az bozan taş)
+		idx := r.autoDiscardIndexLocked(hand)
+		if idx < 0 {
+			idx = 0
+		}
+		tileID := hand[idx]
+		hand[idx] = hand[len(hand)-1]
+		hand = hand[:len(hand)-1]
+		r.Hands[r.TurnSeat] = hand
+		uid := ""
+		if p, ok := r.Players[r.TurnSeat]; ok && p != nil {
+			uid = p.UserID
+		}
+		r.pushDiscardLocked(DiscardEvent{
+			TileID: tileID,
+			Seat:   r.TurnSeat,
+			UserID: uid,
+			At:     time.Now().Unix(),
+		})
+		r.discardedSeats[r.TurnSeat] = true
+
+		// bitiş (101: açmış oyuncunun son taşı) -> el biter
+		if done, _ := r.rules().DetectFinish(hand, len(r.OpenedMelds[r.TurnSeat]) > 0, r.OkeyTileID); done {
+			r.finishHandLocked(r.TurnSeat, tileID)
+			return
+		}
+	}
+
+	// tur ilerlet
+	r.TurnSeat = r.seatAfter(r.TurnSeat)
+	r.turnNo++
+	r.takeReverted = false
+	r.TurnPhase = "WAIT_DRAW"
+	r.Updated = time.Now().Unix()
+
+	// discard sonrası çekme bitmişse -> el bitir (en temiz nokta)
+	if len(r.DrawPile) == 0 {
+		r.endHandLocked()
+		return
+	}
+
+	r.resetTurnTimerLocked()
+}
+
+// discardRiskLocked: taş atılırsa yazılacak ceza (okey / işlek)
+func (r *Room) discardRiskLocked(hand []string) map[string]int {
+	risk := make(map[string]int, len(hand))
+	if r.Config.PenaltyMode != PenaltyOn || !r.rules().HasTable() {
+		return risk
+	}
+	for _, id := range hand {
+		if parseTile(id, "", r.OkeyTileID).IsRealOkey {
+			risk[id] = r.Config.Scoring.OkeyDiscardPenalty
+		} else if r.isPlayableLocked(id) {
+			risk[id] = r.Config.Scoring.PlayableDiscardPenalty
+		}
+	}
+	return risk
+}
+
+// autoDiscardIndexLocked: DISCARD_SUGGEST değerlendirmesinin ilk sırası;
+// sonuç yoksa eski "en küçük taş" kuralı.
+func (r *Room) autoDiscardIndexLocked(hand []string) int {
+	opts := RankDiscards(hand, r.Indicator, r.OkeyTileID, r.discardRiskLocked(hand), AutoDiscardBudget)
+	if len(opts) > 0 {
+		for i, id := range hand {
+			if id == opts[0].TileID {
+				return i
+			}
+		}
+	}
+	return pickAutoDiscardIndex(hand)
+}
+
 func (r *Room) onTurnTimeout(gen int64) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -1261,44 +1965,7 @@ func (r *Room) onTurnTimeout(gen int64) {
 		return
 
 	case "WAIT_DISCARD":
-		hand := r.Hands[r.TurnSeat]
-		if len(hand) > 0 {
-			// ✅ gerçek DISCARD (en küçük taş)
-			idx := pickAutoDiscardIndex(hand)
-			if idx < 0 {
-				idx = 0
-			}
-			tileID := hand[idx]
-			hand[idx] = hand[len(hand)-1]
-			hand = hand[:len(hand)-1]
-			r.Hands[r.TurnSeat] = hand
-			uid := ""
-			if p, ok := r.Players[r.TurnSeat]; ok && p != nil {
-				uid = p.UserID
-			}
-			r.Discards = append(r.Discards, DiscardEvent{
-				TileID: tileID,
-				Seat:   r.TurnSeat,
-				UserID: uid,
-				At:     time.Now().Unix(),
-			})
-
-
-		}
-
-		// tur ilerlet
-		r.TurnSeat = nextSeat(r.TurnSeat)
-		r.TurnPhase = "WAIT_DRAW"
-		r.Updated = time.Now().Unix()
-
-		// discard sonrası çekme bitmişse -> el bitir (en temiz nokta)
-		if len(r.DrawPile) == 0 {
-			r.endHandLocked()
-			go r.broadcastSnapshot()
-			return
-		}
-
-		r.resetTurnTimerLocked()
+		r.autoDiscardLocked()
 		go r.broadcastSnapshot()
 		return
 
@@ -1365,18 +2032,44 @@ func (r *Room) discard(userID string, tileID string) error {
 	}
 	if idx < 0 { return errors.New("tile not in hand") }
 
+	// yandan alınan taş kullanılmadıysa: taş geri konur, ceza, desteden çekilir
+	if r.TakenDiscard != "" && r.TakenDiscardSeat == userSeat {
+		// tur süresi yenilenmez: geri koy / tekrar al ile masa kilitlenmesin
+		r.revertTakenDiscardLocked(userSeat)
+		r.Updated = time.Now().Unix()
+		go r.broadcastSnapshot()
+		return errors.New("taken discard was not used; tile returned, draw from the pile")
+	}
+
 	hand[idx] = hand[len(hand)-1]
 	hand = hand[:len(hand)-1]
 	r.Hands[userSeat] = hand
-	r.Discards = append(r.Discards, DiscardEvent{
+	r.pushDiscardLocked(DiscardEvent{
 		TileID: tileID,
 		Seat:   userSeat,
 		UserID: userID,
 		At:     time.Now().Unix(),
 	})
+	r.discardedSeats[userSeat] = true
 
+	// ✅ bitti mi? (101: açmış oyuncu son taşını attı, okey: el perlere ayrılıyor)
+	if done, _ := r.rules().DetectFinish(hand, len(r.OpenedMelds[userSeat]) > 0, r.OkeyTileID); done {
+		r.finishHandLocked(userSeat, tileID)
+		return nil
+	}
+
+	// ceza modu: okey atma / işlek taşı atma (101)
+	if r.Config.PenaltyMode == PenaltyOn && r.rules().HasTable() {
+		if parseTile(tileID, "", r.OkeyTileID).IsRealOkey {
+			r.addPenaltyLocked(userSeat, r.Config.Scoring.OkeyDiscardPenalty, PenaltyOkeyDiscard)
+		} else if r.isPlayableLocked(tileID) {
+			r.addPenaltyLocked(userSeat, r.Config.Scoring.PlayableDiscardPenalty, PenaltyPlayableDiscard)
+		}
+	}
 
-	r.TurnSeat = nextSeat(r.TurnSeat)
+	r.TurnSeat = r.seatAfter(r.TurnSeat)
+	r.turnNo++
+	r.takeReverted = false
 	r.TurnPhase = "WAIT_DRAW"
 	r.Updated = time.Now().Unix()
 
@@ -1393,37 +2086,484 @@ func (r *Room) discard(userID string, tileID string) error {
 }
 
 /* =========================
-   HTTP + WS
+   YANDAN ALMA (DRAW_DISCARD)
    ========================= */
 
-func healthHandler(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write([]byte("OK"))
+// pushDiscardLocked: taş atan oyuncunun kendi destesine + geçmişe
+func (r *Room) pushDiscardLocked(ev DiscardEvent) {
+	r.Discards = append(r.Discards, ev)
+	r.DiscardStacks[ev.Seat] = append(r.DiscardStacks[ev.Seat], ev)
 }
 
-type HelloPayload struct{ UserID string `json:"userId"` }
-type RoomCreatePayload struct {
-    UserID string `json:"userId"`
-    Config *RoomConfig `json:"config,omitempty"`
-}
+// drawDiscard: soldaki oyuncunun atık destesinin üstündeki taşı alır.
+// 101'de taş aynı turda el açmada veya işlekte kullanılmak zorundadır.
+func (r *Room) drawDiscard(userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-type RoomJoinPayload struct {
-	UserID string `json:"userId"`
-	RoomID string `json:"roomId"`
-}
-type DiceStopPayload struct {
-	UserID string `json:"userId"`
-	RoomID string `json:"roomId"`
-}
-type DrawPayload struct {
-	UserID string `json:"userId"`
-	RoomID string `json:"roomId"`
-}
-type DiscardPayload struct {
-	UserID string `json:"userId"`
-	RoomID string `json:"roomId"`
-	TileID string `json:"tileId"`
-}
+	if r.State != "PLAYING" { return errors.New("game not started") }
+	if r.TurnPhase != "WAIT_DRAW" { return errors.New("not in WAIT_DRAW phase") }
+
+	userSeat := 0
+	for s, p := range r.Players {
+		if p.UserID == userID { userSeat = s; break }
+	}
+	if userSeat == 0 { return errors.New("user not in room") }
+	if userSeat != r.TurnSeat { return errors.New("not your turn") }
+	if r.takeReverted { return errors.New("taken discard was returned this turn; draw from the pile") }
+
+	left := r.seatBefore(userSeat)
+	stack := r.DiscardStacks[left]
+	n := len(stack)
+	if n == 0 { return errors.New("left player has no discard") }
+	top := stack[n-1]
+	r.DiscardStacks[left] = stack[:n-1]
+
+	// geçmişte kim aldı işaretle
+	r.takenHistIdx = -1
+	for i := len(r.Discards) - 1; i >= 0; i-- {
+		if r.Discards[i].TileID == top.TileID && r.Discards[i].TakenBy == 0 {
+			r.Discards[i].TakenBy = userSeat
+			r.takenHistIdx = i
+			break
+		}
+	}
+
+	r.Hands[userSeat] = append(r.Hands[userSeat], top.TileID)
+	// düz okeyde yandan alınan taşı kullanma zorunluluğu yok
+	if r.rules().HasTable() {
+		r.TakenDiscard = top.TileID
+		r.TakenDiscardSeat = userSeat
+		r.takenEvent = top
+	}
+
+	r.TurnPhase = "WAIT_DISCARD"
+	r.resetTurnTimerLocked()
+	r.Updated = time.Now().Unix()
+	return nil
+}
+
+// markTakenUsedLocked: yandan alınan taş elden çıktıysa zorunluluk kalkar
+func (r *Room) markTakenUsedLocked(seat int) {
+	if r.TakenDiscard == "" || r.TakenDiscardSeat != seat {
+		return
+	}
+	for _, id := range r.Hands[seat] {
+		if id == r.TakenDiscard {
+			return
+		}
+	}
+	r.TakenDiscard = ""
+	r.TakenDiscardSeat = 0
+}
+
+// revertTakenDiscardLocked: kullanılmayan taş soldakinin önüne geri konur,
+// ceza modu açıksa ceza yazılır ve oyuncu desteden çekmeye döner.
+func (r *Room) revertTakenDiscardLocked(seat int) {
+	hand := r.Hands[seat]
+	for i := range hand {
+		if hand[i] == r.TakenDiscard {
+			hand = append(hand[:i], hand[i+1:]...)
+			break
+		}
+	}
+	r.Hands[seat] = hand
+	ev := r.takenEvent
+	r.DiscardStacks[ev.Seat] = append(r.DiscardStacks[ev.Seat], ev)
+	if r.takenHistIdx >= 0 && r.takenHistIdx < len(r.Discards) {
+		r.Discards[r.takenHistIdx].TakenBy = 0
+	}
+
+	if r.Config.PenaltyMode == PenaltyOn {
+		r.addPenaltyLocked(seat, r.Config.Scoring.UnusedTakePenalty, PenaltyUnusedTake)
+	}
+
+	r.TakenDiscard = ""
+	r.TakenDiscardSeat = 0
+	r.takenEvent = DiscardEvent{}
+	r.takenHistIdx = -1
+	r.takeReverted = true
+	r.TurnPhase = "WAIT_DRAW"
+}
+
+// isPlayableLocked: taş masadaki herhangi bir pere işlenebilir mi?
+func (r *Room) isPlayableLocked(tileID string) bool {
+	rules := r.rules()
+	for _, ms := range r.OpenedMelds {
+		for _, m := range ms {
+			if _, err := rules.ValidateLayoff(m, tileID, "", r.OkeyTileID); err == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wrongOpeningLocked: ceza modu açıksa hatalı el açma cezası yazılır,
+// taşlar elde kalır ve otomatik taş atılır. Tekrar açmaya engel yok.
+func (r *Room) wrongOpeningLocked(seat int, err error) error {
+	if r.Config.PenaltyMode != PenaltyOn || !errors.Is(err, errWrongOpening) {
+		return err
+	}
+	r.addPenaltyLocked(seat, r.Config.Scoring.WrongOpenPenalty, PenaltyWrongOpen)
+	r.autoDiscardLocked()
+	r.Updated = time.Now().Unix()
+	go r.broadcastSnapshot()
+	return err
+}
+
+func (r *Room) addPenaltyLocked(seat int, points int, reason string) {
+	// ev kuralıyla kapatılmış ceza (0) yazılmaz
+	if points == 0 {
+		return
+	}
+	uid := ""
+	if p, ok := r.Players[seat]; ok && p != nil {
+		uid = p.UserID
+	}
+	r.Penalties = append(r.Penalties, PenaltyEvent{
+		Seat:   seat,
+		UserID: uid,
+		Points: points,
+		Reason: reason,
+		At:     time.Now().Unix(),
+	})
+}
+
+/* =========================
+   GÖSTERGE (SHOW_INDICATOR)
+   ========================= */
+
+// showIndicator: göstergenin eşini elinde tutan oyuncu, ilk taşını
+// atmadan önce kendi sırasında gösterir.
+func (r *Room) showIndicator(userID string, tileID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.State != "PLAYING" { return errors.New("game not started") }
+
+	userSeat := 0
+	for s, p := range r.Players {
+		if p.UserID == userID { userSeat = s; break }
+	}
+	if userSeat == 0 { return errors.New("user not in room") }
+	if userSeat != r.TurnSeat { return errors.New("not your turn") }
+	if r.discardedSeats[userSeat] { return errors.New("only before your first discard") }
+	if r.IndicatorShownBy != 0 { return errors.New("indicator already shown") }
+	if tileID == "" { return errors.New("tileId required") }
+	if tileBase(tileID) != tileBase(r.Indicator) { return errors.New("tile does not match the indicator") }
+
+	found := false
+	for _, id := range r.Hands[userSeat] {
+		if id == tileID { found = true; break }
+	}
+	if !found { return errors.New("tile not in hand") }
+
+	r.IndicatorShownBy = userSeat
+	r.broadcastLocked(OutMsg{T: "INDICATOR_SHOWN", P: map[string]any{
+		"seat":   userSeat,
+		"userId": userID,
+		"tileId": tileID,
+	}})
+
+	r.Updated = time.Now().Unix()
+	return nil
+}
+
+/* =========================
+   EL AÇMA (OPEN_HAND)
+   ========================= */
+
+func (r *Room) resetTableLocked() {
+	r.OpenedMelds = make(map[int][]TableMeld, 4)
+	r.OpenedPoints = make(map[int]int, 4)
+	r.PairOpeners = make(map[int]bool, 4)
+	r.RunThreshold = OpenMinPoints
+	r.PairThreshold = OpenMinPairs
+	r.KatlamaLevel = 0
+	r.TableEvents = nil
+	r.Penalties = nil
+	r.TakenDiscard = ""
+	r.TakenDiscardSeat = 0
+	r.takeReverted = false
+	r.turnNo = 0
+	r.openedTurn = make(map[int]int, 4)
+	r.finishSeat = 0
+	r.finishTile = ""
+	r.IndicatorShownBy = 0
+	r.discardedSeats = make(map[int]bool, 4)
+}
+
+func (r *Room) openHand(userID string, mode OpenMode, melds []MeldInput) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.State != "PLAYING" { return errors.New("game not started") }
+	if r.TurnPhase != "WAIT_DISCARD" { return errors.New("not in WAIT_DISCARD phase") }
+
+	userSeat := 0
+	for s, p := range r.Players {
+		if p.UserID == userID { userSeat = s; break }
+	}
+	if userSeat == 0 { return errors.New("user not in room") }
+	if userSeat != r.TurnSeat { return errors.New("not your turn") }
+
+	if mode == OpenPair {
+		return r.openPairsLocked(userSeat, melds)
+	}
+	if mode != "" && mode != OpenRun { return errors.New("invalid open mode") }
+	if r.PairOpeners[userSeat] { return errors.New("pair opener can only lay off pairs") }
+	if len(r.OpenedMelds[userSeat]) > 0 { return errors.New("hand already opened") }
+
+	opened, rest, total, err := r.rules().ValidateOpening(r.Hands[userSeat], OpenRun, melds, r.OkeyTileID)
+	if err == nil && total < r.RunThreshold {
+		err = fmt.Errorf("%w: total %d is below %d", errWrongOpening, total, r.RunThreshold)
+	}
+	if err != nil { return r.wrongOpeningLocked(userSeat, err) }
+
+	// taşlar elden masaya (oyuncunun kendi alanı)
+	r.Hands[userSeat] = rest
+	r.OpenedMelds[userSeat] = opened
+	r.OpenedPoints[userSeat] = total
+	r.openedTurn[userSeat] = r.turnNo
+	r.raiseThresholdLocked(OpenRun, total)
+	r.markTakenUsedLocked(userSeat)
+
+	r.Updated = time.Now().Unix()
+	return nil
+}
+
+// raiseThresholdLocked: açılıştan sonra eşikleri kurallara göre günceller
+// (katlamalıda sonraki açan öncekini geçmek zorunda).
+func (r *Room) raiseThresholdLocked(mode OpenMode, value int) {
+	openers := 0
+	for _, ms := range r.OpenedMelds {
+		if len(ms) > 0 { openers++ }
+	}
+	t := r.rules().RaiseThreshold(Thresholds{
+		Run:   r.RunThreshold,
+		Pair:  r.PairThreshold,
+		Level: r.KatlamaLevel,
+	}, mode, value, openers)
+	r.RunThreshold, r.PairThreshold, r.KatlamaLevel = t.Run, t.Pair, t.Level
+}
+
+// openPairsLocked: çift açma. Çift açan oyuncu sonraki turlarda
+// sadece yeni çift indirebilir (alt sınır yok).
+func (r *Room) openPairsLocked(seat int, pairs []MeldInput) error {
+	alreadyOpened := len(r.OpenedMelds[seat]) > 0
+	if alreadyOpened && !r.PairOpeners[seat] {
+		return errors.New("run opener cannot lay down pairs")
+	}
+
+	opened, rest, count, err := r.rules().ValidateOpening(r.Hands[seat], OpenPair, pairs, r.OkeyTileID)
+	if err == nil && !alreadyOpened && count < r.PairThreshold {
+		err = fmt.Errorf("%w: needs at least %d pairs, got %d", errWrongOpening, r.PairThreshold, count)
+	}
+	if err != nil {
+		// sonraki çift indirmeleri işlek sayılır: ceza yok, taş geri
+		if alreadyOpened { return err }
+		return r.wrongOpeningLocked(seat, err)
+	}
+
+	r.Hands[seat] = rest
+	r.OpenedMelds[seat] = append(r.OpenedMelds[seat], opened...)
+	r.OpenedPoints[seat] += count
+	r.PairOpeners[seat] = true
+	if !alreadyOpened {
+		r.openedTurn[seat] = r.turnNo
+		r.raiseThresholdLocked(OpenPair, count)
+	}
+	r.markTakenUsedLocked(seat)
+
+	r.Updated = time.Now().Unix()
+	return nil
+}
+
+/* =========================
+   İŞLEK (LAYOFF)
+   ========================= */
+
+// layoff: açmış oyuncu elindeki taşı masadaki bir pere işler.
+// targetSeat == 0 ise otomatik işlek: taşın uyduğu ilk per seçilir.
+// Hatalı işlekte taş elden hiç çıkmaz (geri verilmiş sayılır).
+func (r *Room) layoff(userID string, tileID string, targetSeat int, meldIdx int, side LayoffSide) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.State != "PLAYING" { return errors.New("game not started") }
+	if r.TurnPhase != "WAIT_DISCARD" { return errors.New("not in WAIT_DISCARD phase") }
+
+	userSeat := 0
+	for s, p := range r.Players {
+		if p.UserID == userID { userSeat = s; break }
+	}
+	if userSeat == 0 { return errors.New("user not in room") }
+	if userSeat != r.TurnSeat { return errors.New("not your turn") }
+	if !r.rules().HasTable() { return fmt.Errorf("no layoff in %s mode", r.Config.GameMode) }
+	if len(r.OpenedMelds[userSeat]) == 0 { return errors.New("open your hand first") }
+	if r.PairOpeners[userSeat] { return errors.New("pair opener can only lay off pairs") }
+	if tileID == "" { return errors.New("tileId required") }
+
+	hand := r.Hands[userSeat]
+	idx := -1
+	for i := range hand {
+		if hand[i] == tileID { idx = i; break }
+	}
+	if idx < 0 { return errors.New("tile not in hand") }
+
+	var (
+		newMeld TableMeld
+		err     error
+	)
+	if targetSeat == 0 {
+		// otomatik işlek
+		err = errors.New("no meld accepts this tile")
+		for s := 1; s <= r.seatCount() && targetSeat == 0; s++ {
+			for i, m := range r.OpenedMelds[s] {
+				if nm, e := r.rules().ValidateLayoff(m, tileID, side, r.OkeyTileID); e == nil {
+					targetSeat, meldIdx, newMeld, err = s, i, nm, nil
+					break
+				}
+			}
+		}
+	} else {
+		ms := r.OpenedMelds[targetSeat]
+		if meldIdx < 0 || meldIdx >= len(ms) { return errors.New("meld not found") }
+		newMeld, err = r.rules().ValidateLayoff(ms[meldIdx], tileID, side, r.OkeyTileID)
+	}
+	if err != nil { return err }
+
+	r.OpenedMelds[targetSeat][meldIdx] = newMeld
+	hand[idx] = hand[len(hand)-1]
+	r.Hands[userSeat] = hand[:len(hand)-1]
+	r.markTakenUsedLocked(userSeat)
+
+	// son taş işlendi -> el biter
+	if done, _ := r.rules().DetectFinish(r.Hands[userSeat], true, r.OkeyTileID); done {
+		r.finishHandLocked(userSeat, "")
+	}
+
+	r.Updated = time.Now().Unix()
+	return nil
+}
+
+/* =========================
+   OKEY ALMA (OKEY_SWAP)
+   ========================= */
+
+// okeySwap: açmış oyuncu, masadaki perde gerçek okeyin yerine
+// temsil ettiği taşı koyar; okey oyuncunun eline geçer.
+func (r *Room) okeySwap(userID string, tileID string, targetSeat int, meldIdx int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.State != "PLAYING" { return errors.New("game not started") }
+	if r.TurnPhase != "WAIT_DISCARD" { return errors.New("not in WAIT_DISCARD phase") }
+
+	userSeat := 0
+	for s, p := range r.Players {
+		if p.UserID == userID { userSeat = s; break }
+	}
+	if userSeat == 0 { return errors.New("user not in room") }
+	if userSeat != r.TurnSeat { return errors.New("not your turn") }
+	if !r.rules().HasTable() { return fmt.Errorf("no okey swap in %s mode", r.Config.GameMode) }
+	if len(r.OpenedMelds[userSeat]) == 0 { return errors.New("open your hand first") }
+	if tileID == "" { return errors.New("tileId required") }
+
+	hand := r.Hands[userSeat]
+	idx := -1
+	for i := range hand {
+		if hand[i] == tileID { idx = i; break }
+	}
+	if idx < 0 { return errors.New("tile not in hand") }
+
+	ms := r.OpenedMelds[targetSeat]
+	if meldIdx < 0 || meldIdx >= len(ms) { return errors.New("meld not found") }
+	if r.PairOpeners[userSeat] && ms[meldIdx].Type != MeldPair {
+		return errors.New("pair opener can only lay off pairs")
+	}
+
+	newMeld, okeyID, err := swapOkeyInMeld(ms[meldIdx], tileID, r.OkeyTileID)
+	if err != nil { return err }
+
+	ms[meldIdx] = newMeld
+	hand[idx] = okeyID
+	r.Hands[userSeat] = hand
+
+	r.TableEvents = append(r.TableEvents, TableEvent{
+		Kind:       "OKEY_SWAP",
+		Seat:       userSeat,
+		TargetSeat: targetSeat,
+		MeldIdx:    meldIdx,
+		TileID:     tileID,
+		OkeyID:     okeyID,
+		At:         time.Now().Unix(),
+	})
+	r.markTakenUsedLocked(userSeat)
+
+	r.Updated = time.Now().Unix()
+	return nil
+}
+
+/* =========================
+   HTTP + WS
+   ========================= */
+
+func healthHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("OK"))
+}
+
+type HelloPayload struct{ UserID string `json:"userId"` }
+type RoomCreatePayload struct {
+    UserID string `json:"userId"`
+    Config *RoomConfigInput `json:"config,omitempty"`
+}
+
+type RoomJoinPayload struct {
+	UserID string `json:"userId"`
+	RoomID string `json:"roomId"`
+}
+type DiceStopPayload struct {
+	UserID string `json:"userId"`
+	RoomID string `json:"roomId"`
+}
+type DrawPayload struct {
+	UserID string `json:"userId"`
+	RoomID string `json:"roomId"`
+}
+type DiscardPayload struct {
+	UserID string `json:"userId"`
+	RoomID string `json:"roomId"`
+	TileID string `json:"tileId"`
+}
+type ShowIndicatorPayload struct {
+	UserID string `json:"userId"`
+	RoomID string `json:"roomId"`
+	TileID string `json:"tileId"`
+}
+type OpenHandPayload struct {
+	UserID string      `json:"userId"`
+	RoomID string      `json:"roomId"`
+	Mode   OpenMode    `json:"mode,omitempty"` // "RUN" | "PAIR" ("" = RUN)
+	Melds  []MeldInput `json:"melds"`
+}
+type LayoffPayload struct {
+	UserID     string     `json:"userId"`
+	RoomID     string     `json:"roomId"`
+	TileID     string     `json:"tileId"`
+	TargetSeat int        `json:"targetSeat"`     // 0 = otomatik işlek
+	MeldIdx    int        `json:"meldIdx"`        // targetSeat alanındaki per sırası
+	Side       LayoffSide `json:"side,omitempty"` // "START" | "END" (seri için)
+}
+type OkeySwapPayload struct {
+	UserID     string `json:"userId"`
+	RoomID     string `json:"roomId"`
+	TileID     string `json:"tileId"` // okeyin temsil ettiği taş
+	TargetSeat int    `json:"targetSeat"`
+	MeldIdx    int    `json:"meldIdx"`
+}
 
 func wsHandler(w http.ResponseWriter, r *http.Request) {
 	ws, err := upgrader.Upgrade(w, r, nil)
@@ -1436,19 +2576,28 @@ func wsHandler(w http.ResponseWriter, r *http.Request) {
 	readPump(c)
 }
 
-func normalizeConfig(in *RoomConfig) (RoomConfig, error) {
+func normalizeConfig(in *RoomConfigInput) (RoomConfig, error) {
     // default
     cfg := RoomConfig{
         GameMode:    GameModeClassic,
         PenaltyMode: PenaltyOn,
+        TeamMode:    TeamModeSolo,
         HandCount:   1,
+
+        EndCondition: EndByHands,
+
+        FakeIndicator: FakeIndicatorNextTile,
+        Direction:     DirectionCW,
+        Seats:         4,
+
+        Scoring: defaultScoreRules(),
     }
     if in == nil {
         return cfg, nil
     }
 
     if in.GameMode != "" {
-        if in.GameMode != GameModeClassic && in.GameMode != GameModeKatlamali {
+        if _, ok := rulesByMode[in.GameMode]; !ok {
             return cfg, errors.New("invalid gameMode")
         }
         cfg.GameMode = in.GameMode
@@ -1461,6 +2610,13 @@ func normalizeConfig(in *RoomConfig) (RoomConfig, error) {
         cfg.PenaltyMode = in.PenaltyMode
     }
 
+    if in.TeamMode != "" {
+        if in.TeamMode != TeamModeSolo && in.TeamMode != TeamModeTeam {
+            return cfg, errors.New("invalid teamMode")
+        }
+        cfg.TeamMode = in.TeamMode
+    }
+
     if in.HandCount != 0 {
         if in.HandCount < 1 || in.HandCount > 11 {
             return cfg, errors.New("handCount must be 1..11")
@@ -1468,6 +2624,55 @@ func normalizeConfig(in *RoomConfig) (RoomConfig, error) {
         cfg.HandCount = in.HandCount
     }
 
+    if in.EndCondition != "" {
+        cfg.EndCondition = in.EndCondition
+    }
+    switch cfg.EndCondition {
+    case EndByHands:
+    case EndByPoints:
+        if in.PointLimit < 101 || in.PointLimit > 9999 {
+            return cfg, errors.New("pointLimit must be 101..9999")
+        }
+        cfg.PointLimit = in.PointLimit
+    case EndByTime:
+        if in.TimeLimitMin < 5 || in.TimeLimitMin > 240 {
+            return cfg, errors.New("timeLimitMin must be 5..240")
+        }
+        cfg.TimeLimitMin = in.TimeLimitMin
+    default:
+        return cfg, errors.New("invalid endCondition")
+    }
+
+    if in.FakeIndicator != "" {
+        if in.FakeIndicator != FakeIndicatorNextTile && in.FakeIndicator != FakeIndicatorRedeal {
+            return cfg, errors.New("invalid fakeIndicator")
+        }
+        cfg.FakeIndicator = in.FakeIndicator
+    }
+
+    if in.Direction != "" {
+        if in.Direction != DirectionCW && in.Direction != DirectionCCW {
+            return cfg, errors.New("invalid direction")
+        }
+        cfg.Direction = in.Direction
+    }
+
+    if in.Seats != 0 {
+        if in.Seats < 2 || in.Seats > 4 {
+            return cfg, errors.New("seats must be 2..4")
+        }
+        cfg.Seats = in.Seats
+    }
+    if cfg.TeamMode == TeamModeTeam && cfg.Seats != 4 {
+        return cfg, errors.New("teamMode TEAM requires 4 seats")
+    }
+
+    scoring, err := normalizeScoreRules(in.Scoring)
+    if err != nil {
+        return cfg, err
+    }
+    cfg.Scoring = scoring
+
     return cfg, nil
 }
 
@@ -1688,6 +2893,32 @@ func readPump(c *Conn) {
 			}
 			room.broadcastSnapshot()
 
+		case "DRAW_DISCARD":
+			var p DrawPayload
+			_ = json.Unmarshal(in.P, &p)
+			uid := p.UserID
+			if uid == "" { uid = c.userID }
+			if uid == "" {
+				sendErr(c, in.ReqID, "MISSING_USER", "userId required")
+				continue
+			}
+			roomID := p.RoomID
+			if roomID == "" { roomID = c.roomID }
+			if roomID == "" {
+				sendErr(c, in.ReqID, "MISSING_ROOM", "roomId required")
+				continue
+			}
+			room, ok := rooms.GetRoom(roomID)
+			if !ok {
+				sendErr(c, in.ReqID, "ROOM_NOT_FOUND", "room not found")
+				continue
+			}
+			if err := room.drawDiscard(uid); err != nil {
+				sendErr(c, in.ReqID, "DRAW_DISCARD_REJECTED", err.Error())
+				continue
+			}
+			room.broadcastSnapshot()
+
 		case "DISCARD":
 			var p DiscardPayload
 			_ = json.Unmarshal(in.P, &p)
@@ -1714,11 +2945,226 @@ func readPump(c *Conn) {
 			}
 			room.broadcastSnapshot()
 
+		case "SHOW_INDICATOR":
+			var p ShowIndicatorPayload
+			_ = json.Unmarshal(in.P, &p)
+			uid := p.UserID
+			if uid == "" { uid = c.userID }
+			if uid == "" {
+				sendErr(c, in.ReqID, "MISSING_USER", "userId required")
+				continue
+			}
+			roomID := p.RoomID
+			if roomID == "" { roomID = c.roomID }
+			if roomID == "" {
+				sendErr(c, in.ReqID, "MISSING_ROOM", "roomId required")
+				continue
+			}
+			room, ok := rooms.GetRoom(roomID)
+			if !ok {
+				sendErr(c, in.ReqID, "ROOM_NOT_FOUND", "room not found")
+				continue
+			}
+			if err := room.showIndicator(uid, p.TileID); err != nil {
+				sendErr(c, in.ReqID, "SHOW_INDICATOR_REJECTED", err.Error())
+				continue
+			}
+			room.broadcastSnapshot()
+
+		case "OPEN_HAND":
+			var p OpenHandPayload
+			_ = json.Unmarshal(in.P, &p)
+			uid := p.UserID
+			if uid == "" { uid = c.userID }
+			if uid == "" {
+				sendErr(c, in.ReqID, "MISSING_USER", "userId required")
+				continue
+			}
+			roomID := p.RoomID
+			if roomID == "" { roomID = c.roomID }
+			if roomID == "" {
+				sendErr(c, in.ReqID, "MISSING_ROOM", "roomId required")
+				continue
+			}
+			room, ok := rooms.GetRoom(roomID)
+			if !ok {
+				sendErr(c, in.ReqID, "ROOM_NOT_FOUND", "room not found")
+				continue
+			}
+			if err := room.openHand(uid, OpenMode(strings.ToUpper(strings.TrimSpace(string(p.Mode)))), p.Melds); err != nil {
+				sendErr(c, in.ReqID, "OPEN_REJECTED", err.Error())
+				continue
+			}
+			room.broadcastSnapshot()
+
+		case "LAYOFF":
+			var p LayoffPayload
+			_ = json.Unmarshal(in.P, &p)
+			uid := p.UserID
+			if uid == "" { uid = c.userID }
+			if uid == "" {
+				sendErr(c, in.ReqID, "MISSING_USER", "userId required")
+				continue
+			}
+			roomID := p.RoomID
+			if roomID == "" { roomID = c.roomID }
+			if roomID == "" {
+				sendErr(c, in.ReqID, "MISSING_ROOM", "roomId required")
+				continue
+			}
+			room, ok := rooms.GetRoom(roomID)
+			if !ok {
+				sendErr(c, in.ReqID, "ROOM_NOT_FOUND", "room not found")
+				continue
+			}
+			side := LayoffSide(strings.ToUpper(strings.TrimSpace(string(p.Side))))
+			if err := room.layoff(uid, p.TileID, p.TargetSeat, p.MeldIdx, side); err != nil {
+				sendErr(c, in.ReqID, "LAYOFF_REJECTED", err.Error())
+				continue
+			}
+			room.broadcastSnapshot()
+
+		case "OKEY_SWAP":
+			var p OkeySwapPayload
+			_ = json.Unmarshal(in.P, &p)
+			uid := p.UserID
+			if uid == "" { uid = c.userID }
+			if uid == "" {
+				sendErr(c, in.ReqID, "MISSING_USER", "userId required")
+				continue
+			}
+			roomID := p.RoomID
+			if roomID == "" { roomID = c.roomID }
+			if roomID == "" {
+				sendErr(c, in.ReqID, "MISSING_ROOM", "roomId required")
+				continue
+			}
+			room, ok := rooms.GetRoom(roomID)
+			if !ok {
+				sendErr(c, in.ReqID, "ROOM_NOT_FOUND", "room not found")
+				continue
+			}
+			if err := room.okeySwap(uid, p.TileID, p.TargetSeat, p.MeldIdx); err != nil {
+				sendErr(c, in.ReqID, "OKEY_SWAP_REJECTED", err.Error())
+				continue
+			}
+			room.broadcastSnapshot()
+
 
 
 		case "ROOMS_LIST_REQUEST":
 			rooms.BroadcastRoomsList()
 
+		case "DISCARD_SUGGEST":
+			var p struct {
+				RoomID string `json:"roomId"`
+				UserID string `json:"userId"`
+			}
+			_ = json.Unmarshal(in.P, &p)
+
+			if p.RoomID == "" || p.UserID == "" {
+				sendErr(c, in.ReqID, "BAD_REQUEST", "roomId and userId required")
+				continue
+			}
+
+			r, ok := rooms.GetRoom(p.RoomID)
+			if !ok {
+				sendErr(c, in.ReqID, "ROOM_NOT_FOUND", "room not found")
+				continue
+			}
+
+			if c.userID != p.UserID {
+				sendErr(c, in.ReqID, "FORBIDDEN", "user mismatch")
+				continue
+			}
+
+			seat := r.seatOf(p.UserID)
+			if seat == 0 {
+				sendErr(c, in.ReqID, "NOT_IN_ROOM", "user not seated")
+				continue
+			}
+
+			// --- el + ceza riski (işlek kontrolü masaya bakar)
+			r.mu.RLock()
+			hand := append([]string(nil), r.Hands[seat]...)
+			indicator := r.Indicator
+			realOkeyBase := r.OkeyTileID
+			risk := r.discardRiskLocked(hand)
+			r.mu.RUnlock()
+
+			opts := RankDiscards(hand, indicator, realOkeyBase, risk, 60*time.Millisecond)
+
+			send(c, OutMsg{
+				T:     "DISCARD_SUGGESTED",
+				ReqID: in.ReqID,
+				P: map[string]any{
+					"roomId":   p.RoomID,
+					"userId":   p.UserID,
+					"handHash": handHash(hand),
+					"options":  opts,
+				},
+			})
+
+		case "LAYOFF_SUGGEST":
+			var p struct {
+				RoomID string `json:"roomId"`
+				UserID string `json:"userId"`
+			}
+			_ = json.Unmarshal(in.P, &p)
+
+			if p.RoomID == "" || p.UserID == "" {
+				sendErr(c, in.ReqID, "BAD_REQUEST", "roomId and userId required")
+				continue
+			}
+
+			r, ok := rooms.GetRoom(p.RoomID)
+			if !ok {
+				sendErr(c, in.ReqID, "ROOM_NOT_FOUND", "room not found")
+				continue
+			}
+
+			if c.userID != p.UserID {
+				sendErr(c, in.ReqID, "FORBIDDEN", "user mismatch")
+				continue
+			}
+
+			seat := r.seatOf(p.UserID)
+			if seat == 0 {
+				sendErr(c, in.ReqID, "NOT_IN_ROOM", "user not seated")
+				continue
+			}
+
+			// --- el + masadaki perler (kopya)
+			r.mu.RLock()
+			hand := append([]string(nil), r.Hands[seat]...)
+			table := make(map[int][]TableMeld, len(r.OpenedMelds))
+			for s, ms := range r.OpenedMelds {
+				table[s] = append([]TableMeld(nil), ms...)
+			}
+			opened := len(r.OpenedMelds[seat]) > 0 && r.rules().HasTable()
+			pairOpener := r.PairOpeners[seat]
+			realOkeyBase := r.OkeyTileID
+			okeyHeld := r.Config.Scoring.OkeyHeldPenalty
+			r.mu.RUnlock()
+
+			// açmamış oyuncu işlek yapamaz: liste boş
+			opts := []LayoffOption{}
+			if opened {
+				opts = FindLayoffs(hand, table, realOkeyBase, okeyHeld, pairOpener)
+			}
+
+			send(c, OutMsg{
+				T:     "LAYOFF_SUGGESTED",
+				ReqID: in.ReqID,
+				P: map[string]any{
+					"roomId":   p.RoomID,
+					"userId":   p.UserID,
+					"handHash": handHash(hand),
+					"opened":   opened,
+					"options":  opts,
+				},
+			})
+
 		case "MELD_SUGGEST":
 			var p struct {
 				RoomID string `json:"roomId"`
@@ -1754,7 +3200,10 @@ func readPump(c *Conn) {
 			hand := append([]string(nil), r.Hands[seat]...)
 			indicator := r.Indicator       // örn "R07-1"
 			realOkeyBase := r.OkeyTileID   // örn "R08"
+			runMin, pairMin := r.RunThreshold, r.PairThreshold // katlamalıda yükselir
 			r.mu.RUnlock()
+			if runMin == 0 { runMin = OpenMinPoints }
+			if pairMin == 0 { pairMin = OpenMinPairs }
 
 			hh := handHash(hand)
 			mode := strings.ToUpper(strings.TrimSpace(p.Mode))
@@ -1771,6 +3220,8 @@ func readPump(c *Conn) {
 			r.mu.RUnlock()
 
 			if ok {
+				// eşik el içinde değişebilir: cache'deki sonuca güncel eşik
+				cached.applyOpening(runMin, pairMin)
 				send(c, OutMsg{
 					T:     "MELD_SUGGESTED",
 					ReqID: in.ReqID,
@@ -1812,6 +3263,8 @@ func readPump(c *Conn) {
 			r.SolverCache[cacheKey] = res
 			r.mu.Unlock()
 
+			res.applyOpening(runMin, pairMin)
+
 			send(c, OutMsg{
 				T:     "MELD_SUGGESTED",
 				ReqID: in.ReqID,
@@ -0,0 +1,158 @@
+package main
+
+import "errors"
+
+// DealLayout: varyantın dağıtım düzeni
+type DealLayout struct {
+	PilesPerSeat int // oyuncu başı deste (başlayana 1. destenin fazla taşı)
+}
+
+// Thresholds: el açma alt sınırları + katlama seviyesi (el içinde değişir)
+type Thresholds struct {
+	Run   int
+	Pair  int
+	Level int
+}
+
+// Rules: oyun varyantının kuralları, RoomConfig.GameMode ile seçilir.
+// Room akışı (draw / discard / timeout / el sonu) kuralları sadece buradan sorar;
+// yeni varyant ya da ev kuralı için yeni bir Rules yazıp rulesByMode'a eklemek yeter.
+type Rules interface {
+	Mode() GameMode
+	DealLayout() DealLayout
+
+	// HasTable: el açma, işlek, okey alma ve 101 cezaları var mı
+	HasTable() bool
+	// ValidateOpening: açılan perler, elde kalanlar, değer (seri: puan, çift: adet)
+	ValidateOpening(hand []string, mode OpenMode, melds []MeldInput, okeyBase string) ([]TableMeld, []string, int, error)
+	ValidateLayoff(m TableMeld, tileID string, side LayoffSide, okeyBase string) (TableMeld, error)
+	// RaiseThreshold: bir oyuncu açtıktan sonraki eşikler (openers: açmış oyuncu sayısı)
+	RaiseThreshold(t Thresholds, mode OpenMode, value int, openers int) Thresholds
+	HandMultiplier(level int, sr ScoreRules) int
+
+	// DetectFinish: taş attıktan / işledikten sonra el bitti mi, çiftten mi
+	DetectFinish(hand []string, opened bool, okeyBase string) (bool, bool)
+	ScoreHand(in handScoreInput) map[int]int
+}
+
+var rulesByMode = map[GameMode]Rules{
+	GameModeClassic:   classicRules{},
+	GameModeKatlamali: katlamaliRules{},
+	GameModeOkey:      okeyRules{},
+}
+
+// rulesFor: bilinmeyen mod klasik 101 sayılır
+func rulesFor(mode GameMode) Rules {
+	if rs, ok := rulesByMode[mode]; ok {
+		return rs
+	}
+	return classicRules{}
+}
+
+func (r *Room) rules() Rules {
+	return rulesFor(r.Config.GameMode)
+}
+
+/* =========================
+   CLASSIC_101
+   ========================= */
+
+type classicRules struct{}
+
+func (classicRules) Mode() GameMode { return GameModeClassic }
+
+func (classicRules) DealLayout() DealLayout { return DealLayout{PilesPerSeat: PilesPerSeat} }
+
+func (classicRules) HasTable() bool { return true }
+
+func (classicRules) ValidateOpening(hand []string, mode OpenMode, melds []MeldInput, okeyBase string) ([]TableMeld, []string, int, error) {
+	switch mode {
+	case OpenPair:
+		return validatePairOpening(hand, melds, okeyBase)
+	case OpenRun, "":
+		return validateOpening(hand, melds, okeyBase)
+	}
+	return nil, nil, 0, errors.New("invalid open mode")
+}
+
+func (classicRules) ValidateLayoff(m TableMeld, tileID string, side LayoffSide, okeyBase string) (TableMeld, error) {
+	return layoffMeld(m, tileID, side, okeyBase)
+}
+
+func (classicRules) RaiseThreshold(t Thresholds, mode OpenMode, value int, openers int) Thresholds {
+	return t
+}
+
+func (classicRules) HandMultiplier(level int, sr ScoreRules) int { return 1 }
+
+// açmış oyuncunun elinde taş kalmadı
+func (classicRules) DetectFinish(hand []string, opened bool, okeyBase string) (bool, bool) {
+	return opened && len(hand) == 0, false
+}
+
+func (classicRules) ScoreHand(in handScoreInput) map[int]int { return scoreHand(in) }
+
+/* =========================
+   KATLAMALI_101
+   ========================= */
+
+// katlamaliRules: klasik 101 + sonraki açan öncekini geçmek zorunda,
+// her katlamada el puanları katlanır.
+type katlamaliRules struct{ classicRules }
+
+func (katlamaliRules) Mode() GameMode { return GameModeKatlamali }
+
+// İlk açılıştan sonraki her açılış bir katlama sayılır.
+func (katlamaliRules) RaiseThreshold(t Thresholds, mode OpenMode, value int, openers int) Thresholds {
+	if openers > 1 {
+		t.Level++
+	}
+	switch mode {
+	case OpenPair:
+		if value+1 > t.Pair { t.Pair = value + 1 }
+	default:
+		if value+1 > t.Run { t.Run = value + 1 }
+	}
+	return t
+}
+
+func (katlamaliRules) HandMultiplier(level int, sr ScoreRules) int {
+	m := 1
+	for i := 0; i < level; i++ {
+		m *= sr.KatlamaMultiplier
+	}
+	return m
+}
+
+/* =========================
+   OKEY (düz okey)
+   ========================= */
+
+// okeyRules: 14 taş, açma / işlek yok; kalan el perlere ya da 7 çifte ayrılınca biter.
+type okeyRules struct{}
+
+func (okeyRules) Mode() GameMode { return GameModeOkey }
+
+func (okeyRules) DealLayout() DealLayout { return DealLayout{PilesPerSeat: OkeyPilesPerSeat} }
+
+func (okeyRules) HasTable() bool { return false }
+
+func (okeyRules) ValidateOpening(hand []string, mode OpenMode, melds []MeldInput, okeyBase string) ([]TableMeld, []string, int, error) {
+	return nil, nil, 0, errors.New("no opening in OKEY mode")
+}
+
+func (okeyRules) ValidateLayoff(m TableMeld, tileID string, side LayoffSide, okeyBase string) (TableMeld, error) {
+	return TableMeld{}, errors.New("no layoff in OKEY mode")
+}
+
+func (okeyRules) RaiseThreshold(t Thresholds, mode OpenMode, value int, openers int) Thresholds {
+	return t
+}
+
+func (okeyRules) HandMultiplier(level int, sr ScoreRules) int { return 1 }
+
+func (okeyRules) DetectFinish(hand []string, opened bool, okeyBase string) (bool, bool) {
+	return okeyHandFinish(hand, okeyBase)
+}
+
+func (okeyRules) ScoreHand(in handScoreInput) map[int]int { return scoreOkeyHand(in) }
@@ -0,0 +1,390 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ScoreRules: ev kuralı puan tablosu (RoomConfig.scoring).
+// Oyun başlayınca config ile kilitlenir.
+type ScoreRules struct {
+	// el içi cezalar (ceza modu açıkken)
+	WrongOpenPenalty       int `json:"wrongOpenPenalty"`
+	UnusedTakePenalty      int `json:"unusedTakePenalty"`
+	PlayableDiscardPenalty int `json:"playableDiscardPenalty"`
+	OkeyDiscardPenalty     int `json:"okeyDiscardPenalty"`
+
+	// el sonu
+	NotOpenedPenalty int `json:"notOpenedPenalty"`
+	WinnerBonus      int `json:"winnerBonus"`
+	OkeyHeldPenalty  int `json:"okeyHeldPenalty"`
+	IndicatorBonus   int `json:"indicatorBonus"`
+	IndicatorPenalty int `json:"indicatorPenalty"`
+
+	// çarpanlar
+	PairOpenMultiplier   int `json:"pairOpenMultiplier"`
+	OkeyFinishMultiplier int `json:"okeyFinishMultiplier"`
+	HandFinishMultiplier int `json:"handFinishMultiplier"`
+	PairFinishMultiplier int `json:"pairFinishMultiplier"`
+	KatlamaMultiplier    int `json:"katlamaMultiplier"`
+
+	// düz okey
+	OkeyLosePoints      int `json:"okeyLosePoints"`
+	OkeyIndicatorPoints int `json:"okeyIndicatorPoints"`
+}
+
+// ScoreRulesInput: ROOM_CREATE ile gelen puan tablosu.
+// Gönderilmeyen alan standart değeri alır; 0 gönderilirse kural kapanır (çarpanlar hariç).
+type ScoreRulesInput struct {
+	WrongOpenPenalty       *int `json:"wrongOpenPenalty"`
+	UnusedTakePenalty      *int `json:"unusedTakePenalty"`
+	PlayableDiscardPenalty *int `json:"playableDiscardPenalty"`
+	OkeyDiscardPenalty     *int `json:"okeyDiscardPenalty"`
+
+	NotOpenedPenalty *int `json:"notOpenedPenalty"`
+	WinnerBonus      *int `json:"winnerBonus"`
+	OkeyHeldPenalty  *int `json:"okeyHeldPenalty"`
+	IndicatorBonus   *int `json:"indicatorBonus"`
+	IndicatorPenalty *int `json:"indicatorPenalty"`
+
+	PairOpenMultiplier   *int `json:"pairOpenMultiplier"`
+	OkeyFinishMultiplier *int `json:"okeyFinishMultiplier"`
+	HandFinishMultiplier *int `json:"handFinishMultiplier"`
+	PairFinishMultiplier *int `json:"pairFinishMultiplier"`
+	KatlamaMultiplier    *int `json:"katlamaMultiplier"`
+
+	OkeyLosePoints      *int `json:"okeyLosePoints"`
+	OkeyIndicatorPoints *int `json:"okeyIndicatorPoints"`
+}
+
+func defaultScoreRules() ScoreRules {
+	return ScoreRules{
+		WrongOpenPenalty:       WrongOpenPenalty,
+		UnusedTakePenalty:      UnusedTakePenalty,
+		PlayableDiscardPenalty: PlayableDiscardPenalty,
+		OkeyDiscardPenalty:     OkeyDiscardPenalty,
+
+		NotOpenedPenalty: NotOpenedPenalty,
+		WinnerBonus:      WinnerBonus,
+		OkeyHeldPenalty:  OkeyHeldPenalty,
+		IndicatorBonus:   IndicatorBonus,
+		IndicatorPenalty: IndicatorPenalty,
+
+		PairOpenMultiplier:   PairOpenMultiplier,
+		OkeyFinishMultiplier: OkeyFinishMultiplier,
+		HandFinishMultiplier: HandFinishMultiplier,
+		PairFinishMultiplier: PairFinishMultiplier,
+		KatlamaMultiplier:    KatlamaMultiplier,
+
+		OkeyLosePoints:      OkeyLosePoints,
+		OkeyIndicatorPoints: OkeyIndicatorPoints,
+	}
+}
+
+// normalizeScoreRules: default bas + aralık kontrolü (nil = hepsi standart)
+func normalizeScoreRules(in *ScoreRulesInput) (ScoreRules, error) {
+	out := defaultScoreRules()
+	if in == nil {
+		return out, nil
+	}
+	fields := []struct {
+		name   string
+		in     *int
+		dst    *int
+		lo, hi int
+	}{
+		{"wrongOpenPenalty", in.WrongOpenPenalty, &out.WrongOpenPenalty, 0, 1010},
+		{"unusedTakePenalty", in.UnusedTakePenalty, &out.UnusedTakePenalty, 0, 1010},
+		{"playableDiscardPenalty", in.PlayableDiscardPenalty, &out.PlayableDiscardPenalty, 0, 1010},
+		{"okeyDiscardPenalty", in.OkeyDiscardPenalty, &out.OkeyDiscardPenalty, 0, 1010},
+		{"notOpenedPenalty", in.NotOpenedPenalty, &out.NotOpenedPenalty, 0, 2020},
+		{"winnerBonus", in.WinnerBonus, &out.WinnerBonus, 0, 1010},
+		{"okeyHeldPenalty", in.OkeyHeldPenalty, &out.OkeyHeldPenalty, 0, 1010},
+		{"indicatorBonus", in.IndicatorBonus, &out.IndicatorBonus, 0, 1010},
+		{"indicatorPenalty", in.IndicatorPenalty, &out.IndicatorPenalty, 0, 1010},
+		{"pairOpenMultiplier", in.PairOpenMultiplier, &out.PairOpenMultiplier, 1, 4},
+		{"okeyFinishMultiplier", in.OkeyFinishMultiplier, &out.OkeyFinishMultiplier, 1, 4},
+		{"handFinishMultiplier", in.HandFinishMultiplier, &out.HandFinishMultiplier, 1, 4},
+		{"pairFinishMultiplier", in.PairFinishMultiplier, &out.PairFinishMultiplier, 1, 4},
+		{"katlamaMultiplier", in.KatlamaMultiplier, &out.KatlamaMultiplier, 1, 4},
+		{"okeyLosePoints", in.OkeyLosePoints, &out.OkeyLosePoints, 0, 20},
+		{"okeyIndicatorPoints", in.OkeyIndicatorPoints, &out.OkeyIndicatorPoints, 0, 20},
+	}
+	for _, f := range fields {
+		if f.in == nil {
+			continue
+		}
+		if *f.in < f.lo || *f.in > f.hi {
+			return out, fmt.Errorf("scoring.%s must be %d..%d", f.name, f.lo, f.hi)
+		}
+		*f.dst = *f.in
+	}
+	return out, nil
+}
+
+// handScoreInput: el sonu puanlama için gereken her şey
+type handScoreInput struct {
+	seats       []int
+	hands       map[int][]string
+	opened      map[int]bool
+	pairOpeners map[int]bool
+	penalties   []PenaltyEvent
+	okeyBase    string
+
+	winner     int // 0 = kazanan yok (deste bitti)
+	okeyFinish bool
+	handFinish bool
+	pairFinish bool
+
+	multiplier int  // oyun modu çarpanı (katlamalı), 0 = 1
+	teams      bool // takım modu: bitirenin eşi de bitirmiş sayılır
+
+	indicatorBy int // göstergeyi gösteren seat (0 = yok)
+
+	score ScoreRules // odanın puan tablosu
+}
+
+// handTileValue: elde kalan taşın ceza değeri
+func handTileValue(id string, okeyBase string, okeyHeld int) int {
+	t, ok := resolveTableTile(id, okeyBase)
+	if !ok {
+		return 0
+	}
+	if t.wild {
+		return okeyHeld
+	}
+	return t.num
+}
+
+// finishMultiplier: özel bitişler katlanarak uygulanır
+func finishMultiplier(in handScoreInput) int {
+	m := 1
+	if in.multiplier > 1 {
+		m = in.multiplier
+	}
+	if in.winner == 0 {
+		return m
+	}
+	if in.okeyFinish {
+		m *= in.score.OkeyFinishMultiplier
+	}
+	if in.handFinish {
+		m *= in.score.HandFinishMultiplier
+	}
+	if in.pairFinish {
+		m *= in.score.PairFinishMultiplier
+	}
+	return m
+}
+
+// scoreHand: seat -> el puanı (ceza pozitif, kazanan bonusu negatif).
+// Değerler odanın ScoreRules tablosundan gelir.
+//   - açmayan: NotOpenedPenalty
+//   - açan: eldeki taşların toplamı (gerçek okey OkeyHeldPenalty)
+//   - çift açan: cezası PairOpenMultiplier ile katlanır
+//   - özel bitiş (okey / elden / çift): herkesin cezası ve bonus katlanır
+//   - katlamalıda katlama çarpanı da aynı şekilde uygulanır
+//   - gösterge bonusu / cezası ve el içi ceza kayıtları olduğu gibi eklenir
+func scoreHand(in handScoreInput) map[int]int {
+	mult := finishMultiplier(in)
+	out := make(map[int]int, len(in.seats))
+
+	for _, seat := range in.seats {
+		if seat == in.winner {
+			out[seat] = -in.score.WinnerBonus * mult
+			continue
+		}
+		if in.teams && in.winner != 0 && teamOf(seat) == teamOf(in.winner) {
+			// eş bitirdi: takım için el bitti, ceza yok
+			out[seat] = 0
+			continue
+		}
+		pts := in.score.NotOpenedPenalty
+		if in.opened[seat] {
+			pts = 0
+			for _, id := range in.hands[seat] {
+				pts += handTileValue(id, in.okeyBase, in.score.OkeyHeldPenalty)
+			}
+		}
+		if in.pairOpeners[seat] {
+			pts *= in.score.PairOpenMultiplier
+		}
+		out[seat] = pts * mult
+	}
+
+	// gösterge: gösterene bonus, diğerlerine (takımda rakiplere) ceza
+	if in.indicatorBy != 0 {
+		for _, seat := range in.seats {
+			switch {
+			case seat == in.indicatorBy:
+				out[seat] -= in.score.IndicatorBonus
+			case in.teams && teamOf(seat) == teamOf(in.indicatorBy):
+			default:
+				out[seat] += in.score.IndicatorPenalty
+			}
+		}
+	}
+
+	for _, p := range in.penalties {
+		out[p.Seat] += p.Points
+	}
+	return out
+}
+
+// scoreOkeyHand: düz okey el puanı (ceza pozitif, düşük olan önde).
+//   - bitiremeyen her oyuncu OkeyLosePoints yazar, bitiren 0
+//   - okey atarak / çiftten bitişte katlanır
+//   - gösterge: gösteren hariç herkese (takımda rakiplere) OkeyIndicatorPoints
+func scoreOkeyHand(in handScoreInput) map[int]int {
+	mult := 1
+	if in.okeyFinish {
+		mult *= in.score.OkeyFinishMultiplier
+	}
+	if in.pairFinish {
+		mult *= in.score.PairFinishMultiplier
+	}
+
+	out := make(map[int]int, len(in.seats))
+	for _, seat := range in.seats {
+		switch {
+		case in.winner == 0, seat == in.winner:
+			out[seat] = 0
+		case in.teams && teamOf(seat) == teamOf(in.winner):
+			out[seat] = 0
+		default:
+			out[seat] = in.score.OkeyLosePoints * mult
+		}
+	}
+
+	if in.indicatorBy != 0 {
+		for _, seat := range in.seats {
+			if seat == in.indicatorBy || (in.teams && teamOf(seat) == teamOf(in.indicatorBy)) {
+				continue
+			}
+			out[seat] += in.score.OkeyIndicatorPoints
+		}
+	}
+
+	for _, p := range in.penalties {
+		out[p.Seat] += p.Points
+	}
+	return out
+}
+
+// Standing: maç sonu sıralama satırı (tekli: 1 seat, takım: 2 seat)
+type Standing struct {
+	Rank     int   `json:"rank"`
+	Team     int   `json:"team,omitempty"`
+	Seats    []int `json:"seats"`
+	Total    int   `json:"total"`    // toplam ceza (düşük olan önde)
+	HandsWon int   `json:"handsWon"` // eşitlikte ilk bakılan
+	Points   int   `json:"points"`   // sıralama puanı (O)
+}
+
+// rankingPoints: 1. -> +n, 2. -> n-2, 3. -> 0, 4. -> -4 (n = el sayısı).
+// 3 kişide ortadaki n-2 alır; sonuncu her zaman -4.
+// İki taraf varsa (takım) kazanan +n, kaybeden -4.
+func rankingPoints(rank int, entries int, n int) int {
+	var table []int
+	switch entries {
+	case 4:
+		table = []int{n, n - 2, 0, -4}
+	case 3:
+		table = []int{n, n - 2, -4}
+	case 2:
+		table = []int{n, -4}
+	}
+	if rank < 1 || rank > len(table) {
+		return 0
+	}
+	return table[rank-1]
+}
+
+// computeStandings: toplam puana göre küçükten büyüğe sıralar.
+// Eşitlikte: çok el kazanan, sonra en iyi tek el puanı. Bunlar da eşitse
+// aynı sırayı alırlar ve o sıraların puanlarını bölüşürler (seat sadece liste sırası).
+func computeStandings(seats []int, totals map[int]int, rows []HandScoreRow, teams bool, handCount int) []Standing {
+	key := func(seat int) int {
+		if teams {
+			return teamOf(seat)
+		}
+		return seat
+	}
+
+	byKey := map[int]*Standing{}
+	order := []int{}
+	for _, seat := range seats {
+		k := key(seat)
+		st, ok := byKey[k]
+		if !ok {
+			st = &Standing{}
+			if teams {
+				st.Team = k
+			}
+			byKey[k] = st
+			order = append(order, k)
+		}
+		st.Seats = append(st.Seats, seat)
+		st.Total += totals[seat]
+	}
+
+	best := make(map[int]int, len(byKey))
+	for _, row := range rows {
+		if row.WinnerSeat != 0 {
+			if st, ok := byKey[key(row.WinnerSeat)]; ok {
+				st.HandsWon++
+			}
+		}
+		sums := map[int]int{}
+		for seat, pts := range row.Scores {
+			sums[key(seat)] += pts
+		}
+		for k, pts := range sums {
+			if b, ok := best[k]; !ok || pts < b {
+				best[k] = pts
+			}
+		}
+	}
+
+	out := make([]Standing, 0, len(order))
+	for _, k := range order {
+		sort.Ints(byKey[k].Seats)
+		out = append(out, *byKey[k])
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Total != b.Total {
+			return a.Total < b.Total
+		}
+		if a.HandsWon != b.HandsWon {
+			return a.HandsWon > b.HandsWon
+		}
+		ba, bb := best[key(a.Seats[0])], best[key(b.Seats[0])]
+		if ba != bb {
+			return ba < bb
+		}
+		return a.Seats[0] < b.Seats[0]
+	})
+	tied := func(a, b Standing) bool {
+		return a.Total == b.Total && a.HandsWon == b.HandsWon &&
+			best[key(a.Seats[0])] == best[key(b.Seats[0])]
+	}
+	for i := 0; i < len(out); {
+		j := i + 1
+		for j < len(out) && tied(out[i], out[j]) {
+			j++
+		}
+		// i..j-1 berabere: aynı sıra, sıra puanları eşit bölünür (kesir atılır)
+		sum := 0
+		for k := i; k < j; k++ {
+			sum += rankingPoints(k+1, len(out), handCount)
+		}
+		for k := i; k < j; k++ {
+			out[k].Rank = i + 1
+			out[k].Points = sum / (j - i)
+		}
+		i = j
+	}
+	return out
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNormalizeConfigScoringZero(t *testing.T) {
+	var in RoomConfigInput
+	raw := `{"gameMode":"KATLAMALI_101","scoring":{"winnerBonus":0,"okeyDiscardPenalty":0,"katlamaMultiplier":3}}`
+	if err := json.Unmarshal([]byte(raw), &in); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := normalizeConfig(&in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.GameMode != GameModeKatlamali {
+		t.Fatalf("gameMode = %q", cfg.GameMode)
+	}
+	sr := cfg.Scoring
+	if sr.WinnerBonus != 0 || sr.OkeyDiscardPenalty != 0 || sr.KatlamaMultiplier != 3 {
+		t.Fatalf("explicit values not applied: %+v", sr)
+	}
+	// gönderilmeyen alan standart kalır
+	if sr.WrongOpenPenalty != WrongOpenPenalty || sr.NotOpenedPenalty != NotOpenedPenalty {
+		t.Fatalf("defaults not kept: %+v", sr)
+	}
+}
+
+func TestNormalizeScoreRulesRange(t *testing.T) {
+	zero, neg := 0, -1
+	tests := []struct {
+		name string
+		in   ScoreRulesInput
+		ok   bool
+	}{
+		{"zero penalty", ScoreRulesInput{WrongOpenPenalty: &zero}, true},
+		{"zero okey points", ScoreRulesInput{OkeyLosePoints: &zero}, true},
+		{"negative penalty", ScoreRulesInput{WrongOpenPenalty: &neg}, false},
+		{"zero multiplier", ScoreRulesInput{PairOpenMultiplier: &zero}, false},
+	}
+	for _, tc := range tests {
+		_, err := normalizeScoreRules(&tc.in)
+		if (err == nil) != tc.ok {
+			t.Errorf("%s: err = %v, want ok=%v", tc.name, err, tc.ok)
+		}
+	}
+	if sr, err := normalizeScoreRules(nil); err != nil || sr != defaultScoreRules() {
+		t.Errorf("nil input: %+v, %v", sr, err)
+	}
+}
+
+func TestScoreHand(t *testing.T) {
+	// seat 1 bitirir; 2 açmadı; 3 açtı, elinde B05 + okey; 4 çift açtı, elinde B03
+	base := func() handScoreInput {
+		return handScoreInput{
+			seats: []int{1, 2, 3, 4},
+			hands: map[int][]string{
+				2: {"R01-1", "R02-1"},
+				3: {"B05-1", "K10-1"},
+				4: {"B03-1"},
+			},
+			opened:      map[int]bool{1: true, 3: true, 4: true},
+			pairOpeners: map[int]bool{4: true},
+			okeyBase:    "K10",
+			winner:      1,
+			score:       defaultScoreRules(),
+		}
+	}
+	tests := []struct {
+		name string
+		mod  func(in *handScoreInput)
+		want map[int]int
+	}{
+		{"normal finish", nil, map[int]int{1: -101, 2: 202, 3: 106, 4: 6}},
+		{"okey finish", func(in *handScoreInput) { in.okeyFinish = true },
+			map[int]int{1: -202, 2: 404, 3: 212, 4: 12}},
+		{"hand finish", func(in *handScoreInput) { in.handFinish = true },
+			map[int]int{1: -202, 2: 404, 3: 212, 4: 12}},
+		{"pair finish", func(in *handScoreInput) { in.pairFinish = true },
+			map[int]int{1: -202, 2: 404, 3: 212, 4: 12}},
+		{"okey + hand finish", func(in *handScoreInput) { in.okeyFinish, in.handFinish = true, true },
+			map[int]int{1: -404, 2: 808, 3: 424, 4: 24}},
+		{"no winner", func(in *handScoreInput) { in.winner = 0; in.okeyFinish = true },
+			map[int]int{1: 0, 2: 202, 3: 106, 4: 6}},
+		{"katlama", func(in *handScoreInput) { in.multiplier = 2 },
+			map[int]int{1: -202, 2: 404, 3: 212, 4: 12}},
+		{"teams", func(in *handScoreInput) { in.teams = true },
+			map[int]int{1: -101, 2: 202, 3: 0, 4: 6}},
+		{"indicator and penalty", func(in *handScoreInput) {
+			in.indicatorBy = 2
+			in.penalties = []PenaltyEvent{{Seat: 3, Points: 101}}
+		}, map[int]int{1: 0, 2: 101, 3: 308, 4: 107}},
+	}
+	for _, tc := range tests {
+		in := base()
+		if tc.mod != nil {
+			tc.mod(&in)
+		}
+		got := scoreHand(in)
+		for seat, want := range tc.want {
+			if got[seat] != want {
+				t.Errorf("%s: seat %d = %d, want %d (all %v)", tc.name, seat, got[seat], want, got)
+			}
+		}
+	}
+}
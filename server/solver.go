@@ -10,13 +10,15 @@ import (
 type MeldType string
 
 const (
-	MeldRun  MeldType = "RUN"
-	MeldPair MeldType = "PAIR"
+	MeldRun   MeldType = "RUN"
+	MeldGroup MeldType = "GROUP"
+	MeldPair  MeldType = "PAIR"
 )
 
 type Meld struct {
-	Type  MeldType `json:"type"`
-	Tiles []string `json:"tiles"`
+	Type   MeldType `json:"type"`
+	Tiles  []string `json:"tiles"`
+	Points int      `json:"points"` // okey yerine geçtiği taş kadar sayılır
 }
 
 type SolveMode string
@@ -32,8 +34,33 @@ type SolveResult struct {
 	UsedTilesCount int       `json:"usedTilesCount"`
 	UnusedTiles    []string  `json:"unusedTiles"`
 	ModeUsed       SolveMode `json:"modeUsed"`
-	MeetsRun101    bool      `json:"meetsRun101"`
-	MeetsPair5     bool      `json:"meetsPair5"`
+	MeetsRun101    bool      `json:"meetsRun101"` // seri/per puanı eşiği geçiyor (katlamalıda güncel eşik)
+	MeetsPair5     bool      `json:"meetsPair5"`  // çift sayısı eşiği geçiyor
+	Optimal        bool      `json:"optimal"` // süre içinde en iyi dizilim kanıtlandı
+
+	TotalPoints   int `json:"totalPoints"`  // seri + per puanları toplamı (AUTO: en iyi seri planı)
+	PairCount     int `json:"pairCount"`    // AUTO: en iyi çift planı
+	RunThreshold  int `json:"runThreshold"` // kontrol edilen açma eşiği
+	PairThreshold int `json:"pairThreshold"`
+	PointsGap     int `json:"pointsGap"` // eşiğe kalan puan (0 = açabilir)
+	PairsGap      int `json:"pairsGap"`  // eşiğe kalan çift
+}
+
+// applyOpening: açma eşiklerine göre fark ve bayrakları hesaplar
+func (res *SolveResult) applyOpening(runMin, pairMin int) {
+	res.RunThreshold = runMin
+	res.PairThreshold = pairMin
+
+	res.PointsGap = runMin - res.TotalPoints
+	if res.PointsGap < 0 {
+		res.PointsGap = 0
+	}
+	res.PairsGap = pairMin - res.PairCount
+	if res.PairsGap < 0 {
+		res.PairsGap = 0
+	}
+	res.MeetsRun101 = res.TotalPoints > 0 && res.PointsGap == 0
+	res.MeetsPair5 = res.PairCount > 0 && res.PairsGap == 0
 }
 
 type tileInfo struct {
@@ -183,7 +210,9 @@ type jokerCandidate struct {
 
 
 
-func solveRuns(hand []tileInfo, indicatorBase string) (melds []Meld, used map[string]bool, runSum int) {
+// solveRuns: seri (aynı renk ardışık) + per (aynı sayı farklı renk) dizer.
+// groupsFirst: önce 3-4 renkli perler ayrılır, seriler kalandan kurulur.
+func solveRuns(hand []tileInfo, indicatorBase string, groupsFirst bool) (melds []Meld, used map[string]bool, runSum int) {
 	used = make(map[string]bool)
 
 	indColor := ""
@@ -231,6 +260,41 @@ func solveRuns(hand []tileInfo, indicatorBase string) (melds []Meld, used map[st
 		return id, true
 	}
 
+	makeSetFromPool := func(num int, colors []string) ([]string, bool) {
+		tiles := make([]string, 0, len(colors))
+		for _, c := range colors {
+			id, ok := pick(c, num)
+			if !ok {
+				return nil, false
+			}
+			tiles = append(tiles, id)
+		}
+		return tiles, true
+	}
+
+	if groupsFirst {
+		for num := 13; num >= 1; num-- {
+			colors := make([]string, 0, 4)
+			for _, c := range colorOrder {
+				if len(pool[c][num]) > 0 {
+					colors = append(colors, c)
+				}
+			}
+			if len(colors) < 3 {
+				continue
+			}
+			tiles, ok := makeSetFromPool(num, colors)
+			if !ok {
+				continue
+			}
+			melds = append(melds, Meld{Type: MeldGroup, Tiles: tiles})
+			for _, id := range tiles {
+				used[id] = true
+				runSum += num
+			}
+		}
+	}
+
 	splitRunLengths := func(total int) []int {
 		res := []int{}
 		remain := total
@@ -301,18 +365,6 @@ func solveRuns(hand []tileInfo, indicatorBase string) (melds []Meld, used map[st
 		}
 	}
 
-	makeSetFromPool := func(num int, colors []string) ([]string, bool) {
-		tiles := make([]string, 0, len(colors))
-		for _, c := range colors {
-			id, ok := pick(c, num)
-			if !ok {
-				return nil, false
-			}
-			tiles = append(tiles, id)
-		}
-		return tiles, true
-	}
-
 	for num := 13; num >= 1; num-- {
 		poolColors := make(map[string]bool, 4)
 		for _, c := range colorOrder {
@@ -401,7 +453,7 @@ func solveRuns(hand []tileInfo, indicatorBase string) (melds []Meld, used map[st
 		}
 
 		if len(tiles) >= 3 {
-			melds = append(melds, Meld{Type: MeldRun, Tiles: tiles})
+			melds = append(melds, Meld{Type: MeldGroup, Tiles: tiles})
 			for _, id := range tiles {
 				used[id] = true
 				runSum += num
@@ -532,7 +584,7 @@ func solveRuns(hand []tileInfo, indicatorBase string) (melds []Meld, used map[st
 				continue
 			}
 			tiles = append(tiles, jid)
-			melds = append(melds, Meld{Type: MeldRun, Tiles: tiles})
+			melds = append(melds, Meld{Type: MeldGroup, Tiles: tiles})
 			for _, id := range tiles[:len(tiles)-1] {
 				used[id] = true
 				runSum += best.num
@@ -572,15 +624,27 @@ func solveRuns(hand []tileInfo, indicatorBase string) (melds []Meld, used map[st
 
 
 
-func solvePairs(hand []tileInfo) (melds []Meld, used map[string]bool, pairCount int) {
+// solvePairs: en fazla çift. Önce aynı taşlar eşlenir, okey (joker) tek kalan
+// en büyük taşlarla, sonra birbiriyle eşlenir; bu sıra çift sayısını en büyük yapar.
+// Sahte okey okeyin yerine geçtiği taş sayılır.
+func solvePairs(hand []tileInfo, okeyBase string) (melds []Meld, used map[string]bool, pairCount int) {
 	used = make(map[string]bool)
 
 	byBase := make(map[string][]string)
+	num := make(map[string]int)
+	wilds := make([]string, 0, 2)
 	for _, t := range hand {
-		if t.IsNormal {
-			base := fmt.Sprintf("%s%02d", t.Color, t.Num)
-			byBase[base] = append(byBase[base], t.Raw)
+		t2, ok := resolveTableTile(t.Raw, okeyBase)
+		if !ok {
+			continue
+		}
+		if t2.wild {
+			wilds = append(wilds, t.Raw)
+			continue
 		}
+		base := fmt.Sprintf("%s%02d", t2.color, t2.num)
+		byBase[base] = append(byBase[base], t.Raw)
+		num[base] = t2.num
 	}
 
 	keys := make([]string, 0, len(byBase))
@@ -589,21 +653,42 @@ func solvePairs(hand []tileInfo) (melds []Meld, used map[string]bool, pairCount
 	}
 	sort.Strings(keys)
 
+	addPair := func(a, b string) {
+		melds = append(melds, Meld{Type: MeldPair, Tiles: []string{a, b}})
+		used[a] = true
+		used[b] = true
+		pairCount++
+	}
+
+	singles := make([]string, 0, len(keys))
 	for _, k := range keys {
 		ids := byBase[k]
 		for len(ids) >= 2 {
-			a, b := ids[0], ids[1]
+			addPair(ids[0], ids[1])
 			ids = ids[2:]
-			melds = append(melds, Meld{Type: MeldPair, Tiles: []string{a, b}})
-			used[a] = true
-			used[b] = true
-			pairCount++
+		}
+		if len(ids) == 1 {
+			singles = append(singles, k)
 		}
 	}
+
+	// okey + tek taş: büyük sayı çiftin puanını artırır
+	sort.SliceStable(singles, func(i, j int) bool { return num[singles[i]] > num[singles[j]] })
+	for _, k := range singles {
+		if len(wilds) == 0 {
+			break
+		}
+		addPair(byBase[k][len(byBase[k])-1], wilds[0])
+		wilds = wilds[1:]
+	}
+	for len(wilds) >= 2 {
+		addPair(wilds[0], wilds[1])
+		wilds = wilds[2:]
+	}
 	return melds, used, pairCount
 }
 
-func buildResult(hand []string, melds []Meld, used map[string]bool, mode SolveMode) SolveResult {
+func buildResult(hand []string, melds []Meld, used map[string]bool, mode SolveMode, okeyBase string) SolveResult {
 	unused := make([]string, 0, len(hand))
 	for _, id := range hand {
 		if !used[id] {
@@ -626,14 +711,23 @@ func buildResult(hand []string, melds []Meld, used map[string]bool, mode SolveMo
 	})
 
 
-	return SolveResult{
+	res := SolveResult{
 		Melds:          melds,
 		UsedTilesCount: countUsed(used, melds),
 		UnusedTiles:    unused,
 		ModeUsed:       mode,
-		MeetsRun101:    false,
-		MeetsPair5:     false,
 	}
+	for i := range res.Melds {
+		m := &res.Melds[i]
+		m.Points = meldPoints(*m, okeyBase)
+		if m.Type == MeldPair {
+			res.PairCount++
+			continue
+		}
+		res.TotalPoints += m.Points
+	}
+	res.applyOpening(OpenMinPoints, OpenMinPairs)
+	return res
 }
 
 func SuggestMelds(hand []string, indicatorTileID string, realOkeyBase string, mode SolveMode, budget time.Duration) SolveResult {
@@ -645,35 +739,235 @@ func SuggestMelds(hand []string, indicatorTileID string, realOkeyBase string, mo
 		parsed = append(parsed, parseTile(id, indicatorBase, realOkeyBase))
 	}
 
-	makePlan := func(m SolveMode) (melds []Meld, used map[string]bool) {
+	// seri + per: greedy dizilimler (önce seri / önce per) başlangıç olur,
+	// kalan sürede branch-and-bound ile daha yüksek puan aranır
+	runPlan := func() (melds []Meld, used map[string]bool, optimal bool) {
+		ms, u, sum := solveRuns(parsed, indicatorBase, false)
+		if time.Since(start) >= budget {
+			return ms, u, false
+		}
+		ms2, u2, sum2 := solveRuns(parsed, indicatorBase, true)
+		if sum2 > sum || (sum2 == sum && countUsed(u2, ms2) > countUsed(u, ms)) {
+			ms, u = ms2, u2
+		}
+		if time.Since(start) >= budget {
+			return ms, u, false
+		}
+
+		best, optimal := searchOptimalRuns(hand, realOkeyBase, ms, start.Add(budget))
+		used = make(map[string]bool)
+		for _, m := range best {
+			for _, id := range m.Tiles {
+				used[id] = true
+			}
+		}
+		return best, used, optimal
+	}
+
+	makePlan := func(m SolveMode) (melds []Meld, used map[string]bool, optimal bool) {
 		switch m {
 		case SolvePair:
-			ms, u, _ := solvePairs(parsed)
-			return ms, u
-		case SolveRun:
-			ms, u, _ := solveRuns(parsed, indicatorBase)
-			return ms, u
+			// solvePairs okeyler dahil en fazla çifti bulur
+			ms, u, _ := solvePairs(parsed, realOkeyBase)
+			return ms, u, true
 		default:
-			ms, u, _ := solveRuns(parsed, indicatorBase)
-			return ms, u
+			return runPlan()
 		}
 	}
 
 	if mode != SolveAuto {
-		ms, u := makePlan(mode)
-		return buildResult(hand, ms, u, mode)
+		ms, u, opt := makePlan(mode)
+		res := buildResult(hand, ms, u, mode, realOkeyBase)
+		res.Optimal = opt
+		return res
+	}
+
+	// AUTO: melds daha çok taş kullanan plandan gelir; seri puanı ve çift sayısı
+	// (bayraklar, farklar) seçilen plandan bağımsız, iki plandan ayrı ayrı hesaplanır
+	runMs, runU, runOpt := makePlan(SolveRun)
+	pairMs, pairU, _ := makePlan(SolvePair) // çift araması süreye bağlı değil
+	runRes := buildResult(hand, runMs, runU, SolveAuto, realOkeyBase)
+	pairRes := buildResult(hand, pairMs, pairU, SolveAuto, realOkeyBase)
+
+	res := runRes
+	if pairRes.UsedTilesCount > runRes.UsedTilesCount {
+		res = pairRes
 	}
+	res.TotalPoints = runRes.TotalPoints
+	res.PairCount = pairRes.PairCount
+	res.applyOpening(OpenMinPoints, OpenMinPairs)
+	res.Optimal = runOpt
+	return res
+}
 
-	bestMs, bestU := makePlan(SolveRun)
-	bestCount := countUsed(bestU, bestMs)
+// DiscardOption: DISCARD_SUGGEST satırı (Score küçük olan atmaya en uygun)
+type DiscardOption struct {
+	TileID   string `json:"tileId"`
+	Rank     int    `json:"rank"`     // 1 = en iyi atış
+	Loss     int    `json:"loss"`     // seri/per puanı kaybı
+	PairLoss int    `json:"pairLoss"` // çift sayısı kaybı
+	Penalty  int    `json:"penalty"`  // atınca yazılacak ceza (okey / işlek)
+	Score    int    `json:"score"`
+}
 
-	if time.Since(start) < budget {
-		ms2, u2 := makePlan(SolvePair)
-		c2 := countUsed(u2, ms2)
-		if c2 > bestCount {
-			bestMs, bestU, bestCount = ms2, u2, c2
+// pairPointValue: çift kaybını puana çevirir (101 / 5 çift)
+const pairPointValue = OpenMinPoints / OpenMinPairs
+
+// RankDiscards: eldeki her taşı, atılınca en iyi dizilimden ne kadar
+// götürdüğüne göre sıralar. risk: taş -> atınca yazılacak ceza (oda hesaplar).
+// Aynı taşın iki eşi bir kez hesaplanır; süre taşlara bölünür.
+func RankDiscards(hand []string, indicatorTileID string, realOkeyBase string, risk map[string]int, budget time.Duration) []DiscardOption {
+	if len(hand) == 0 {
+		return nil
+	}
+
+	bases := make(map[string]bool, len(hand))
+	for _, id := range hand {
+		bases[tileBase(id)] = true
+	}
+	per := budget / time.Duration(len(bases)+1)
+
+	baseRun := SuggestMelds(hand, indicatorTileID, realOkeyBase, SolveRun, per)
+	basePair := SuggestMelds(hand, indicatorTileID, realOkeyBase, SolvePair, per)
+
+	type eval struct{ loss, pairLoss int }
+	cache := make(map[string]eval, len(bases))
+
+	out := make([]DiscardOption, 0, len(hand))
+	for i, id := range hand {
+		ev, ok := cache[tileBase(id)]
+		if !ok {
+			rest := make([]string, 0, len(hand)-1)
+			rest = append(rest, hand[:i]...)
+			rest = append(rest, hand[i+1:]...)
+
+			run := SuggestMelds(rest, indicatorTileID, realOkeyBase, SolveRun, per)
+			pair := SuggestMelds(rest, indicatorTileID, realOkeyBase, SolvePair, per)
+			ev = eval{
+				loss:     baseRun.TotalPoints - run.TotalPoints,
+				pairLoss: basePair.PairCount - pair.PairCount,
+			}
+			if ev.loss < 0 {
+				ev.loss = 0 // süre yetmediyse eksik arama
+			}
+			cache[tileBase(id)] = ev
 		}
+
+		// açılış potansiyeli: seri ya da çift yolundan hangisi daha çok kaybettiriyorsa
+		potential := ev.loss
+		if pl := ev.pairLoss * pairPointValue; pl > potential {
+			potential = pl
+		}
+		out = append(out, DiscardOption{
+			TileID:   id,
+			Loss:     ev.loss,
+			PairLoss: ev.pairLoss,
+			Penalty:  risk[id],
+			Score:    potential + risk[id],
+		})
 	}
 
-	return buildResult(hand, bestMs, bestU, SolveAuto)
+	// eşitlikte büyük taş önce atılır (elde kalırsa ceza yazar)
+	value := func(id string) int {
+		t, ok := resolveTableTile(id, realOkeyBase)
+		if !ok {
+			return 0
+		}
+		if t.wild {
+			return -1
+		}
+		return t.num
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score < out[j].Score
+		}
+		vi, vj := value(out[i].TileID), value(out[j].TileID)
+		if vi != vj {
+			return vi > vj
+		}
+		return out[i].TileID < out[j].TileID
+	})
+	for i := range out {
+		out[i].Rank = i + 1
+	}
+	return out
+}
+
+// LayoffOption: LAYOFF_SUGGEST satırı (LAYOFF / OKEY_SWAP mesajına hazır)
+type LayoffOption struct {
+	Kind       string     `json:"kind"` // LAYOFF | OKEY_SWAP
+	TileID     string     `json:"tileId"`
+	TargetSeat int        `json:"targetSeat"`
+	MeldIdx    int        `json:"meldIdx"`
+	Side       LayoffSide `json:"side,omitempty"`
+	OkeyID     string     `json:"okeyId,omitempty"` // swap ile ele geçen okey
+	Points     int        `json:"points"`           // elden düşen ceza puanı (swap: net, eksi olabilir)
+}
+
+// FindLayoffs: eldeki her taşın masadaki perlere tüm geçerli işlekleri
+// ve okey almaları. Elden en çok puan düşüren önce. Okey almada el küçülmez:
+// taş düşer ama okey (okeyHeld) ele girer, puan bu net farktır.
+// pairOpener: çift açan sadece çiftlerdeki okeyi alabilir, işlek yapamaz.
+func FindLayoffs(hand []string, table map[int][]TableMeld, okeyBase string, okeyHeld int, pairOpener bool) []LayoffOption {
+	seats := make([]int, 0, len(table))
+	for seat := range table {
+		seats = append(seats, seat)
+	}
+	sort.Ints(seats)
+
+	out := make([]LayoffOption, 0)
+	for _, id := range hand {
+		pts := handTileValue(id, okeyBase, okeyHeld)
+		for _, seat := range seats {
+			for mi, m := range table[seat] {
+				if !pairOpener {
+					sides := []LayoffSide{""}
+					if m.Type == MeldRun {
+						sides = []LayoffSide{SideStart, SideEnd}
+					}
+					for _, side := range sides {
+						if _, err := layoffMeld(m, id, side, okeyBase); err != nil {
+							continue
+						}
+						out = append(out, LayoffOption{
+							Kind:       "LAYOFF",
+							TileID:     id,
+							TargetSeat: seat,
+							MeldIdx:    mi,
+							Side:       side,
+							Points:     pts,
+						})
+					}
+				}
+				if pairOpener && m.Type != MeldPair {
+					continue
+				}
+				if _, okeyID, err := swapOkeyInMeld(m, id, okeyBase); err == nil {
+					out = append(out, LayoffOption{
+						Kind:       "OKEY_SWAP",
+						TileID:     id,
+						TargetSeat: seat,
+						MeldIdx:    mi,
+						OkeyID:     okeyID,
+						Points:     pts - okeyHeld,
+					})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Points != out[j].Points {
+			return out[i].Points > out[j].Points
+		}
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind == "LAYOFF"
+		}
+		if out[i].TargetSeat != out[j].TargetSeat {
+			return out[i].TargetSeat < out[j].TargetSeat
+		}
+		return out[i].MeldIdx < out[j].MeldIdx
+	})
+	return out
 }
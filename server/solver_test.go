@@ -0,0 +1,96 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSuggestPairsWithOkey(t *testing.T) {
+	// okey R08: R08-1 ve R08-2 gerçek okey, sahte okeyler R08 yerine geçer
+	hand := []string{
+		"B05-1", "B05-2", // çift
+		"K11-1", "G03-1", // tek, okeyle eşlenir
+		"R08-1", "R08-2", // okeyler
+		"JOKER-1", "JOKER-2", // sahte okey çifti
+		"B12-1", // okey kalmadı, atılacak taş
+	}
+	res := SuggestMelds(hand, "R07-1", "R08", SolvePair, 50*time.Millisecond)
+	if res.PairCount != 4 || !res.Optimal {
+		t.Fatalf("PairCount = %d optimal = %v, want 4 and true; melds %v", res.PairCount, res.Optimal, res.Melds)
+	}
+
+	in := make([]MeldInput, 0, len(res.Melds))
+	for _, m := range res.Melds {
+		in = append(in, MeldInput{Type: m.Type, Tiles: m.Tiles})
+	}
+	if _, _, n, err := validatePairOpening(hand, in, "R08"); err != nil || n != 4 {
+		t.Fatalf("suggested pairs rejected: n=%d err=%v melds %v", n, err, res.Melds)
+	}
+}
+
+func TestSolvePairsOnlyWilds(t *testing.T) {
+	hand := []tileInfo{
+		parseTile("R08-1", "", "R08"),
+		parseTile("R08-2", "", "R08"),
+	}
+	ms, _, n := solvePairs(hand, "R08")
+	if n != 1 || len(ms) != 1 {
+		t.Fatalf("two okeys should make one pair, got %d %v", n, ms)
+	}
+}
+
+func TestSuggestAutoReportsBothPlans(t *testing.T) {
+	// seri planı 7 taş, çift planı 8 taş kullanır: AUTO çiftleri seçer
+	// ama seri puanı da ayrıca raporlanır
+	hand := []string{
+		"R11-1", "R12-1", "R13-1",
+		"B10-1", "G10-1", "K10-1", "R10-1",
+		"B02-1", "B02-2", "G04-1", "G04-2", "K06-1", "K06-2", "R03-1", "R03-2",
+	}
+	res := SuggestMelds(hand, "B07-1", "B08", SolveAuto, 200*time.Millisecond)
+	run := SuggestMelds(hand, "B07-1", "B08", SolveRun, 200*time.Millisecond)
+	pair := SuggestMelds(hand, "B07-1", "B08", SolvePair, 200*time.Millisecond)
+
+	if len(res.Melds) == 0 || res.Melds[0].Type != MeldPair || run.TotalPoints == 0 {
+		t.Fatalf("expected AUTO to pick pairs: %v (run %d)", res.Melds, run.TotalPoints)
+	}
+	if res.TotalPoints != run.TotalPoints || res.PairCount != pair.PairCount {
+		t.Fatalf("AUTO points/pairs = %d/%d, want %d/%d", res.TotalPoints, res.PairCount, run.TotalPoints, pair.PairCount)
+	}
+	if res.MeetsRun101 != run.MeetsRun101 || res.MeetsPair5 != pair.MeetsPair5 {
+		t.Fatalf("AUTO flags run=%v pair=%v, want %v %v", res.MeetsRun101, res.MeetsPair5, run.MeetsRun101, pair.MeetsPair5)
+	}
+}
+
+func TestFindLayoffsSwapNetPoints(t *testing.T) {
+	// okey R08; masada B04-B05-okey serisi: okey B06 yerine geçer
+	run, err := buildTableMeld([]string{"B04-1", "B05-1", "R08-1"}, MeldRun, "R08")
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := map[int][]TableMeld{2: {run}}
+	opts := FindLayoffs([]string{"B06-1", "B03-1"}, table, "R08", OkeyHeldPenalty, false)
+
+	var layoff, swap *LayoffOption
+	for i := range opts {
+		switch opts[i].Kind {
+		case "LAYOFF":
+			layoff = &opts[i]
+		case "OKEY_SWAP":
+			swap = &opts[i]
+		}
+	}
+	if layoff == nil || swap == nil {
+		t.Fatalf("want both layoff and swap, got %+v", opts)
+	}
+	if layoff.TileID != "B03-1" || layoff.Points != 3 {
+		t.Fatalf("layoff %+v, want B03-1 for 3", *layoff)
+	}
+	// okey ele girer: el küçülmez
+	if swap.TileID != "B06-1" || swap.Points != 6-OkeyHeldPenalty {
+		t.Fatalf("swap %+v, want B06-1 for %d", *swap, 6-OkeyHeldPenalty)
+	}
+	if opts[0].Kind != "LAYOFF" {
+		t.Fatalf("swap sorted before layoff: %+v", opts)
+	}
+}
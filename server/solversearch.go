@@ -0,0 +1,229 @@
+package main
+
+import "time"
+
+// optSearch: seri + per dizilimi için branch-and-bound arama.
+// Amaç açılış puanını (perlerdeki taş değerleri toplamı) en büyük yapmak.
+// Okey taşı yerine geçtiği taşın değerini alır.
+type optSearch struct {
+	ids   [4][14][]string // renk x sayı -> taş id'leri
+	wilds []string        // gerçek okeyler
+
+	remain int // kullanılmamış normal taşların sayı toplamı (üst sınır için)
+
+	cur    []Meld
+	curPts int
+
+	best    []Meld
+	bestPts int
+
+	deadline time.Time
+	nodes    int
+	timedOut bool
+}
+
+var optColors = [4]string{"R", "B", "G", "K"}
+
+// meldPoints: perin açılış puanı (geçersiz per 0)
+func meldPoints(m Meld, okeyBase string) int {
+	tm, err := buildTableMeld(m.Tiles, m.Type, okeyBase)
+	if err != nil {
+		return 0
+	}
+	return tm.Points
+}
+
+// searchOptimalRuns: seed (greedy sonuç) ile başlar, süre dolana kadar daha
+// yüksek puanlı dizilim arar. Dönüş: en iyi dizilim, tüm arama bitti mi (kanıtlı optimum).
+func searchOptimalRuns(hand []string, okeyBase string, seed []Meld, deadline time.Time) ([]Meld, bool) {
+	s := &optSearch{deadline: deadline, best: seed}
+	for _, m := range seed {
+		s.bestPts += meldPoints(m, okeyBase)
+	}
+
+	for _, id := range hand {
+		t, ok := resolveTableTile(id, okeyBase)
+		if !ok {
+			continue
+		}
+		if t.wild {
+			s.wilds = append(s.wilds, id)
+			continue
+		}
+		c, ok := okeyColorIdx[t.color]
+		if !ok || t.num < 1 || t.num > 13 {
+			continue
+		}
+		s.ids[c][t.num] = append(s.ids[c][t.num], id)
+		s.remain += t.num
+	}
+
+	s.rec()
+	return s.best, !s.timedOut
+}
+
+func (s *optSearch) expired() bool {
+	if s.timedOut {
+		return true
+	}
+	s.nodes++
+	if s.nodes&1023 == 0 && time.Now().After(s.deadline) {
+		s.timedOut = true
+	}
+	return s.timedOut
+}
+
+func (s *optSearch) pushMeld(m Meld, pts int) {
+	s.cur = append(s.cur, m)
+	s.curPts += pts
+}
+
+func (s *optSearch) popMeld(pts int) {
+	s.cur = s.cur[:len(s.cur)-1]
+	s.curPts -= pts
+}
+
+func (s *optSearch) take(c, n int) string {
+	ids := s.ids[c][n]
+	id := ids[len(ids)-1]
+	s.ids[c][n] = ids[:len(ids)-1]
+	s.remain -= n
+	return id
+}
+
+func (s *optSearch) put(c, n int, id string) {
+	s.ids[c][n] = append(s.ids[c][n], id)
+	s.remain += n
+}
+
+func (s *optSearch) takeWild() string {
+	id := s.wilds[len(s.wilds)-1]
+	s.wilds = s.wilds[:len(s.wilds)-1]
+	return id
+}
+
+func (s *optSearch) putWild(id string) {
+	s.wilds = append(s.wilds, id)
+}
+
+// rec: en küçük kalan taş ya bir pere girer ya da kullanılmaz
+func (s *optSearch) rec() {
+	if s.expired() {
+		return
+	}
+	if s.curPts > s.bestPts {
+		s.best = append([]Meld(nil), s.cur...)
+		s.bestPts = s.curPts
+	}
+	// üst sınır: kalan her taş ve okey (13) bir pere girse
+	if s.curPts+s.remain+13*len(s.wilds) <= s.bestPts {
+		return
+	}
+
+	c, n := -1, 0
+	for ci := 0; ci < 4 && c < 0; ci++ {
+		for ni := 1; ni <= 13; ni++ {
+			if len(s.ids[ci][ni]) > 0 {
+				c, n = ci, ni
+				break
+			}
+		}
+	}
+	if c < 0 {
+		return
+	}
+
+	id := s.take(c, n)
+
+	s.tryGroups(c, n, id)
+	// seri: bu renkte n altı taş kalmadı, n altı sadece okey olabilir
+	for lead := 0; lead <= len(s.wilds) && n-lead >= 1 && !s.timedOut; lead++ {
+		tiles := make([]string, 0, 13)
+		taken := make([]string, 0, lead)
+		for i := 0; i < lead; i++ {
+			w := s.takeWild()
+			taken = append(taken, w)
+			tiles = append(tiles, w)
+		}
+		tiles = append(tiles, id)
+		s.extendRun(c, n+1, tiles, n-lead)
+		for i := len(taken) - 1; i >= 0; i-- {
+			s.putWild(taken[i])
+		}
+	}
+	// kullanılmaz
+	if !s.timedOut {
+		s.rec()
+	}
+
+	s.put(c, n, id)
+}
+
+// tryGroups: n sayısında, c rengindeki id ile 3-4 renkli per
+func (s *optSearch) tryGroups(c, n int, id string) {
+	others := make([]int, 0, 3)
+	for oc := 0; oc < 4; oc++ {
+		if oc != c && len(s.ids[oc][n]) > 0 {
+			others = append(others, oc)
+		}
+	}
+	for mask := 1<<len(others) - 1; mask >= 0 && !s.timedOut; mask-- {
+		colors := make([]int, 0, 3)
+		for i, oc := range others {
+			if mask&(1<<i) != 0 {
+				colors = append(colors, oc)
+			}
+		}
+		for w := len(s.wilds); w >= 0; w-- {
+			size := 1 + len(colors) + w
+			if size < 3 || size > 4 {
+				continue
+			}
+			tiles := []string{id}
+			for _, oc := range colors {
+				tiles = append(tiles, s.take(oc, n))
+			}
+			for i := 0; i < w; i++ {
+				tiles = append(tiles, s.takeWild())
+			}
+
+			pts := n * size
+			s.pushMeld(Meld{Type: MeldGroup, Tiles: tiles}, pts)
+			s.rec()
+			s.popMeld(pts)
+
+			for i := len(tiles) - 1; i > len(colors); i-- {
+				s.putWild(tiles[i])
+			}
+			for i := len(colors) - 1; i >= 0; i-- {
+				s.put(colors[i], n, tiles[1+i])
+			}
+			if s.timedOut {
+				return
+			}
+		}
+	}
+}
+
+// extendRun: c renginde start'tan başlayan seri, next sıradaki sayı
+func (s *optSearch) extendRun(c, next int, tiles []string, start int) {
+	if len(tiles) >= 3 {
+		pts := sumRange(start, len(tiles))
+		s.pushMeld(Meld{Type: MeldRun, Tiles: append([]string(nil), tiles...)}, pts)
+		s.rec()
+		s.popMeld(pts)
+	}
+	if next > 13 || s.timedOut {
+		return
+	}
+	if len(s.ids[c][next]) > 0 {
+		id := s.take(c, next)
+		s.extendRun(c, next+1, append(tiles, id), start)
+		s.put(c, next, id)
+	}
+	if len(s.wilds) > 0 && !s.timedOut {
+		w := s.takeWild()
+		s.extendRun(c, next+1, append(tiles, w), start)
+		s.putWild(w)
+	}
+}
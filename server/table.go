@@ -0,0 +1,519 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errWrongOpening: kurala aykırı açılış (ceza modunda 101 ceza)
+var errWrongOpening = errors.New("wrong opening")
+
+// TableMeld: masaya açılmış tek bir per (seri / per)
+type TableMeld struct {
+	Type   MeldType `json:"type"`
+	Tiles  []string `json:"tiles"`           // seri ise küçükten büyüğe sıralı
+	Color  string   `json:"color,omitempty"` // seri rengi
+	Start  int      `json:"start,omitempty"` // seri ilk sayı
+	Num    int      `json:"num,omitempty"`   // per sayısı
+	Points int      `json:"points"`
+}
+
+// MeldInput: client'ın gönderdiği per (type boşsa server bulur)
+type MeldInput struct {
+	Type  MeldType `json:"type,omitempty"`
+	Tiles []string `json:"tiles"`
+}
+
+type tableTile struct {
+	id    string
+	color string
+	num   int
+	wild  bool // gerçek okey: her taşın yerine geçer
+}
+
+// resolveTableTile: parseTile üzerinden masadaki değeri çözer.
+// Sahte okey (JOKER) okey taşının kendisi gibi davranır.
+func resolveTableTile(id string, okeyBase string) (tableTile, bool) {
+	ti := parseTile(id, "", okeyBase)
+	switch {
+	case ti.IsRealOkey:
+		return tableTile{id: id, wild: true}, true
+	case ti.IsFakeOkey:
+		if len(okeyBase) != 3 {
+			return tableTile{}, false
+		}
+		n := parseNum2(okeyBase[1:3])
+		if n < 1 || n > 13 {
+			return tableTile{}, false
+		}
+		return tableTile{id: id, color: okeyBase[:1], num: n}, true
+	case ti.IsNormal:
+		return tableTile{id: id, color: ti.Color, num: ti.Num}, true
+	}
+	return tableTile{}, false
+}
+
+func tileIDs(ts []tableTile) []string {
+	out := make([]string, len(ts))
+	for i, t := range ts {
+		out[i] = t.id
+	}
+	return out
+}
+
+// runFromOrdered: verilen sırada seri mi? (okey boşluğu doldurur)
+func runFromOrdered(ts []tableTile) (TableMeld, bool) {
+	if len(ts) < 3 || len(ts) > 13 {
+		return TableMeld{}, false
+	}
+	color := ""
+	start := 0
+	for i, t := range ts {
+		if t.wild {
+			continue
+		}
+		if color == "" {
+			color = t.color
+			start = t.num - i
+		}
+		if t.color != color || t.num != start+i {
+			return TableMeld{}, false
+		}
+	}
+	if color == "" {
+		return TableMeld{}, false
+	}
+	end := start + len(ts) - 1
+	if start < 1 || end > 13 {
+		return TableMeld{}, false
+	}
+	return TableMeld{
+		Type:   MeldRun,
+		Tiles:  tileIDs(ts),
+		Color:  color,
+		Start:  start,
+		Points: sumRange(start, len(ts)),
+	}, true
+}
+
+func validateRun(ts []tableTile) (TableMeld, bool) {
+	if m, ok := runFromOrdered(ts); ok {
+		return m, true
+	}
+	// solver büyükten küçüğe dizer, ters sırayı da kabul et
+	rev := make([]tableTile, len(ts))
+	for i, t := range ts {
+		rev[len(ts)-1-i] = t
+	}
+	return runFromOrdered(rev)
+}
+
+func validateGroup(ts []tableTile) (TableMeld, bool) {
+	if len(ts) < 3 || len(ts) > 4 {
+		return TableMeld{}, false
+	}
+	num := 0
+	colors := make(map[string]bool, 4)
+	for _, t := range ts {
+		if t.wild {
+			continue
+		}
+		if num == 0 {
+			num = t.num
+		}
+		if t.num != num || colors[t.color] {
+			return TableMeld{}, false
+		}
+		colors[t.color] = true
+	}
+	if num == 0 {
+		return TableMeld{}, false
+	}
+	return TableMeld{
+		Type:   MeldGroup,
+		Tiles:  tileIDs(ts),
+		Num:    num,
+		Points: num * len(ts),
+	}, true
+}
+
+// validatePair: aynı iki taş (okey eş olarak kullanılabilir)
+func validatePair(ts []tableTile) (TableMeld, bool) {
+	if len(ts) != 2 {
+		return TableMeld{}, false
+	}
+	a, b := ts[0], ts[1]
+	if !a.wild && !b.wild && (a.color != b.color || a.num != b.num) {
+		return TableMeld{}, false
+	}
+	num := a.num
+	if a.wild {
+		num = b.num
+	}
+	return TableMeld{
+		Type:   MeldPair,
+		Tiles:  tileIDs(ts),
+		Num:    num,
+		Points: num * 2,
+	}, true
+}
+
+// buildTableMeld: id listesinden geçerli per üretir
+func buildTableMeld(ids []string, want MeldType, okeyBase string) (TableMeld, error) {
+	ts := make([]tableTile, 0, len(ids))
+	for _, id := range ids {
+		t, ok := resolveTableTile(id, okeyBase)
+		if !ok {
+			return TableMeld{}, fmt.Errorf("invalid tile %s", id)
+		}
+		ts = append(ts, t)
+	}
+
+	switch want {
+	case MeldRun:
+		if m, ok := validateRun(ts); ok {
+			return m, nil
+		}
+	case MeldGroup:
+		if m, ok := validateGroup(ts); ok {
+			return m, nil
+		}
+	case MeldPair:
+		if m, ok := validatePair(ts); ok {
+			return m, nil
+		}
+	case "":
+		if m, ok := validateRun(ts); ok {
+			return m, nil
+		}
+		if m, ok := validateGroup(ts); ok {
+			return m, nil
+		}
+	default:
+		return TableMeld{}, fmt.Errorf("invalid meld type %s", want)
+	}
+	return TableMeld{}, fmt.Errorf("%w: invalid meld %v", errWrongOpening, ids)
+}
+
+// validatePairOpening: sadece çiftlerden oluşan açılış; dönüşte çift sayısı.
+func validatePairOpening(hand []string, pairs []MeldInput, okeyBase string) ([]TableMeld, []string, int, error) {
+	in := make([]MeldInput, len(pairs))
+	for i, p := range pairs {
+		if p.Type != "" && p.Type != MeldPair {
+			return nil, nil, 0, errors.New("pair opening accepts only pairs")
+		}
+		in[i] = MeldInput{Type: MeldPair, Tiles: p.Tiles}
+	}
+	out, rest, _, err := openMelds(hand, in, okeyBase)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return out, rest, len(out), nil
+}
+
+// validateOpening: seri / per açılışı; çift sadece validatePairOpening ile açılır.
+// Dönüş: açılan perler, elde kalan taşlar, toplam puan.
+func validateOpening(hand []string, melds []MeldInput, okeyBase string) ([]TableMeld, []string, int, error) {
+	for _, m := range melds {
+		if m.Type == MeldPair {
+			return nil, nil, 0, errors.New("run opening accepts only runs and groups")
+		}
+	}
+	return openMelds(hand, melds, okeyBase)
+}
+
+// openMelds: perleri eldeki taşlarla doğrular (tip kontrolü çağırana ait).
+func openMelds(hand []string, melds []MeldInput, okeyBase string) ([]TableMeld, []string, int, error) {
+	if len(melds) == 0 {
+		return nil, nil, 0, errors.New("melds required")
+	}
+
+	inHand := make(map[string]bool, len(hand))
+	for _, id := range hand {
+		inHand[id] = true
+	}
+
+	used := make(map[string]bool)
+	out := make([]TableMeld, 0, len(melds))
+	total := 0
+	for _, in := range melds {
+		for _, id := range in.Tiles {
+			if !inHand[id] {
+				return nil, nil, 0, fmt.Errorf("tile not in hand: %s", id)
+			}
+			if used[id] {
+				return nil, nil, 0, fmt.Errorf("tile used twice: %s", id)
+			}
+			used[id] = true
+		}
+		m, err := buildTableMeld(in.Tiles, in.Type, okeyBase)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		out = append(out, m)
+		total += m.Points
+	}
+
+	rest := make([]string, 0, len(hand)-len(used))
+	for _, id := range hand {
+		if !used[id] {
+			rest = append(rest, id)
+		}
+	}
+	// açtıktan sonra atacak en az 1 taş kalmalı
+	if len(rest) == 0 {
+		return nil, nil, 0, errors.New("must keep a tile to discard")
+	}
+	return out, rest, total, nil
+}
+
+type LayoffSide string
+
+const (
+	SideStart LayoffSide = "START"
+	SideEnd   LayoffSide = "END"
+)
+
+// layoffMeld: masadaki pere tek taş işler (işlek).
+// Seri: iki uçtan uzatma; per: eksik renkle 4'e tamamlama.
+func layoffMeld(m TableMeld, tileID string, side LayoffSide, okeyBase string) (TableMeld, error) {
+	t, ok := resolveTableTile(tileID, okeyBase)
+	if !ok {
+		return TableMeld{}, fmt.Errorf("invalid tile %s", tileID)
+	}
+
+	var ids []string
+	switch m.Type {
+	case MeldRun:
+		end := m.Start + len(m.Tiles) - 1
+		canStart := m.Start > 1 && (t.wild || (t.color == m.Color && t.num == m.Start-1))
+		canEnd := end < 13 && (t.wild || (t.color == m.Color && t.num == end+1))
+		switch {
+		case side == SideStart && canStart, side == "" && canStart && !canEnd:
+			ids = append([]string{tileID}, m.Tiles...)
+		case side == SideEnd && canEnd, side == "" && canEnd:
+			ids = append(append([]string(nil), m.Tiles...), tileID)
+		default:
+			return TableMeld{}, errors.New("tile does not extend the run")
+		}
+
+	case MeldGroup:
+		if len(m.Tiles) >= 4 {
+			return TableMeld{}, errors.New("group is complete")
+		}
+		ids = append(append([]string(nil), m.Tiles...), tileID)
+
+	default:
+		return TableMeld{}, errors.New("cannot lay off onto pairs")
+	}
+
+	out, err := buildTableMeld(ids, m.Type, okeyBase)
+	if err != nil {
+		return TableMeld{}, errors.New("tile does not fit the meld")
+	}
+	return out, nil
+}
+
+// swapOkeyInMeld: perdeki gerçek okeyin temsil ettiği taşı koyup okeyi alır.
+// Dönüş: yeni per ve alınan okey taşı.
+func swapOkeyInMeld(m TableMeld, tileID string, okeyBase string) (TableMeld, string, error) {
+	t, ok := resolveTableTile(tileID, okeyBase)
+	if !ok || t.wild {
+		return TableMeld{}, "", errors.New("swap needs a normal tile")
+	}
+
+	colors := make(map[string]bool, 4)
+	for _, id := range m.Tiles {
+		if mt, ok := resolveTableTile(id, okeyBase); ok && !mt.wild {
+			colors[mt.color] = true
+		}
+	}
+
+	for i, id := range m.Tiles {
+		mt, ok := resolveTableTile(id, okeyBase)
+		if !ok || !mt.wild {
+			continue
+		}
+		fits := false
+		switch m.Type {
+		case MeldRun:
+			fits = t.color == m.Color && t.num == m.Start+i
+		case MeldGroup:
+			fits = t.num == m.Num && !colors[t.color]
+		case MeldPair:
+			// çiftte okey eşinin aynısını temsil eder
+			fits = t.num == m.Num && colors[t.color]
+		}
+		if !fits {
+			continue
+		}
+		ids := append([]string(nil), m.Tiles...)
+		ids[i] = tileID
+		out, err := buildTableMeld(ids, m.Type, okeyBase)
+		if err != nil {
+			continue
+		}
+		return out, id, nil
+	}
+	return TableMeld{}, "", errors.New("no okey in meld represents this tile")
+}
+
+// okeyHand: düz okey bitiş kontrolü için renk x sayı tablosu
+type okeyHand struct {
+	cnt   [4][14]int // renk indeksi x sayı (1..13)
+	wilds int
+	tiles int
+}
+
+var okeyColorIdx = map[string]int{"R": 0, "B": 1, "G": 2, "K": 3}
+
+func newOkeyHand(ids []string, okeyBase string) (okeyHand, bool) {
+	var h okeyHand
+	for _, id := range ids {
+		t, ok := resolveTableTile(id, okeyBase)
+		if !ok {
+			return h, false
+		}
+		h.tiles++
+		if t.wild {
+			h.wilds++
+			continue
+		}
+		c, ok := okeyColorIdx[t.color]
+		if !ok || t.num < 1 || t.num > 13 {
+			return h, false
+		}
+		h.cnt[c][t.num]++
+	}
+	return h, true
+}
+
+// okeyHandFinish: el tamamen perlere (seri / per) ya da 7 çifte ayrılıyor mu?
+// Dönüş: bitti mi, çiftten mi.
+func okeyHandFinish(ids []string, okeyBase string) (bool, bool) {
+	h, ok := newOkeyHand(ids, okeyBase)
+	if !ok || h.tiles != OkeyHandSize {
+		return false, false
+	}
+	if h.sevenPairs() {
+		return true, true
+	}
+	return h.melds(), false
+}
+
+func (h okeyHand) sevenPairs() bool {
+	pairs, singles := 0, 0
+	for c := 0; c < 4; c++ {
+		for n := 1; n <= 13; n++ {
+			pairs += h.cnt[c][n] / 2
+			singles += h.cnt[c][n] % 2
+		}
+	}
+	if singles > h.wilds {
+		return false
+	}
+	// tek kalanlar okeyle eşlenir, artan okeyler kendi aralarında çift
+	return pairs+singles+(h.wilds-singles)/2 == 7
+}
+
+// melds: en küçük taştan başlayıp her perleşme yolunu dener (backtracking)
+func (h *okeyHand) melds() bool {
+	c, n := -1, 0
+	for ci := 0; ci < 4 && c < 0; ci++ {
+		for ni := 1; ni <= 13; ni++ {
+			if h.cnt[ci][ni] > 0 {
+				c, n = ci, ni
+				break
+			}
+		}
+	}
+	if c < 0 {
+		return h.wilds == 0
+	}
+
+	// per: aynı sayı farklı renkler (3-4 taş)
+	h.cnt[c][n]--
+	others := make([]int, 0, 3)
+	for oc := 0; oc < 4; oc++ {
+		if oc != c {
+			others = append(others, oc)
+		}
+	}
+	for mask := 0; mask < 1<<len(others); mask++ {
+		used := make([]int, 0, 3)
+		for i, oc := range others {
+			if mask&(1<<i) != 0 {
+				used = append(used, oc)
+			}
+		}
+		canUse := true
+		for _, oc := range used {
+			if h.cnt[oc][n] == 0 {
+				canUse = false
+			}
+		}
+		if !canUse {
+			continue
+		}
+		for w := 0; w <= h.wilds; w++ {
+			size := 1 + len(used) + w
+			if size < 3 || size > 4 {
+				continue
+			}
+			for _, oc := range used {
+				h.cnt[oc][n]--
+			}
+			h.wilds -= w
+			ok := h.melds()
+			h.wilds += w
+			for _, oc := range used {
+				h.cnt[oc][n]++
+			}
+			if ok {
+				h.cnt[c][n]++
+				return true
+			}
+		}
+	}
+	h.cnt[c][n]++
+
+	// seri: bu renkte n'den küçük taş kalmadı, n altı sadece okey olabilir
+	for lead := 0; lead <= h.wilds && n-lead >= 1; lead++ {
+		h.cnt[c][n]--
+		h.wilds -= lead
+		ok := h.extendRun(c, n+1, 1+lead)
+		h.wilds += lead
+		h.cnt[c][n]++
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// extendRun: c renginde next sayısından devam eden seri (length taş oldu)
+func (h *okeyHand) extendRun(c, next, length int) bool {
+	if length >= 3 && h.melds() {
+		return true
+	}
+	if next > 13 {
+		return false
+	}
+	if h.cnt[c][next] > 0 {
+		h.cnt[c][next]--
+		ok := h.extendRun(c, next+1, length+1)
+		h.cnt[c][next]++
+		if ok {
+			return true
+		}
+	}
+	if h.wilds > 0 {
+		h.wilds--
+		ok := h.extendRun(c, next+1, length+1)
+		h.wilds++
+		if ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+package main
+
+import "testing"
+
+func TestRunOpeningRejectsPairs(t *testing.T) {
+	hand := []string{
+		"R13-1", "R13-2", "B13-1", "B13-2",
+		"G13-1", "G13-2", "K13-1", "K13-2",
+		"B02-1",
+	}
+	pairs := []MeldInput{
+		{Type: MeldPair, Tiles: []string{"R13-1", "R13-2"}},
+		{Type: MeldPair, Tiles: []string{"B13-1", "B13-2"}},
+		{Type: MeldPair, Tiles: []string{"G13-1", "G13-2"}},
+		{Type: MeldPair, Tiles: []string{"K13-1", "K13-2"}},
+	}
+	for _, mode := range []OpenMode{OpenRun, ""} {
+		if _, _, total, err := (classicRules{}).ValidateOpening(hand, mode, pairs, "R05"); err == nil {
+			t.Fatalf("mode %q: pairs accepted as run opening (total %d)", mode, total)
+		}
+	}
+	// aynı çiftler çift açılışında geçerli
+	if _, _, n, err := (classicRules{}).ValidateOpening(hand, OpenPair, pairs, "R05"); err != nil || n != 4 {
+		t.Fatalf("pair opening: n=%d err=%v", n, err)
+	}
+}
+
+func TestBuildTableMeld(t *testing.T) {
+	// okey K10: K10-1/K10-2 gerçek okey, JOKER K10 yerine geçer
+	const okey = "K10"
+	tests := []struct {
+		name   string
+		ids    []string
+		want   MeldType
+		ok     bool
+		points int
+		start  int
+	}{
+		{"run", []string{"B03-1", "B04-1", "B05-1"}, MeldRun, true, 12, 3},
+		{"run reversed", []string{"B05-1", "B04-1", "B03-1"}, MeldRun, true, 12, 3},
+		{"run auto type", []string{"B03-1", "B04-1", "B05-1"}, "", true, 12, 3},
+		{"run mixed colors", []string{"B03-1", "R04-1", "B05-1"}, MeldRun, false, 0, 0},
+		{"run gap", []string{"B03-1", "B05-1", "B06-1"}, MeldRun, false, 0, 0},
+		{"run too short", []string{"B03-1", "B04-1"}, MeldRun, false, 0, 0},
+		{"run 13-1 wrap", []string{"B12-1", "B13-1", "B01-1"}, MeldRun, false, 0, 0},
+		{"run okey middle", []string{"B03-1", "K10-1", "B05-1"}, MeldRun, true, 12, 3},
+		{"run okey start", []string{"K10-1", "B04-1", "B05-1"}, MeldRun, true, 12, 3},
+		{"run okey end", []string{"B11-1", "B12-1", "K10-2"}, MeldRun, true, 36, 11},
+		{"run okey past 13", []string{"B12-1", "B13-1", "K10-1"}, MeldRun, false, 0, 0},
+		{"run fake okey", []string{"K09-1", "JOKER-1", "K11-1"}, MeldRun, true, 30, 9},
+		{"group 3", []string{"R07-1", "B07-1", "G07-1"}, MeldGroup, true, 21, 0},
+		{"group 4", []string{"R07-1", "B07-1", "G07-1", "K07-1"}, MeldGroup, true, 28, 0},
+		{"group okey", []string{"R07-1", "B07-1", "K10-1"}, MeldGroup, true, 21, 0},
+		{"group same color", []string{"R07-1", "R07-2", "B07-1"}, MeldGroup, false, 0, 0},
+		{"group mixed numbers", []string{"R07-1", "B08-1", "G07-1"}, MeldGroup, false, 0, 0},
+		{"group 5 tiles", []string{"R07-1", "B07-1", "G07-1", "K07-1", "K10-1"}, MeldGroup, false, 0, 0},
+		{"pair", []string{"B09-1", "B09-2"}, MeldPair, true, 18, 0},
+		{"pair okey", []string{"K10-1", "B09-1"}, MeldPair, true, 18, 0},
+		{"pair different", []string{"B09-1", "R09-1"}, MeldPair, false, 0, 0},
+		{"pair not auto detected", []string{"B09-1", "B09-2"}, "", false, 0, 0},
+		{"only okeys", []string{"K10-1", "K10-2", "JOKER-1"}, MeldGroup, true, 30, 0},
+		{"bad tile", []string{"X01-1", "B04-1", "B05-1"}, MeldRun, false, 0, 0},
+	}
+	for _, tc := range tests {
+		m, err := buildTableMeld(tc.ids, tc.want, okey)
+		if (err == nil) != tc.ok {
+			t.Errorf("%s: err = %v, want ok=%v", tc.name, err, tc.ok)
+			continue
+		}
+		if !tc.ok {
+			continue
+		}
+		if m.Points != tc.points || m.Start != tc.start {
+			t.Errorf("%s: points %d start %d, want %d %d", tc.name, m.Points, m.Start, tc.points, tc.start)
+		}
+	}
+}
+
+func TestValidateOpening(t *testing.T) {
+	hand := []string{"B03-1", "B04-1", "B05-1", "R07-1", "B07-1", "G07-1", "K01-1"}
+	run := MeldInput{Type: MeldRun, Tiles: []string{"B03-1", "B04-1", "B05-1"}}
+	group := MeldInput{Tiles: []string{"R07-1", "B07-1", "G07-1"}}
+
+	opened, rest, total, err := validateOpening(hand, []MeldInput{run, group}, "K10")
+	if err != nil || len(opened) != 2 || total != 33 || len(rest) != 1 || rest[0] != "K01-1" {
+		t.Fatalf("opened %v rest %v total %d err %v", opened, rest, total, err)
+	}
+
+	bad := []struct {
+		name  string
+		hand  []string
+		melds []MeldInput
+	}{
+		{"no melds", hand, nil},
+		{"tile not in hand", hand, []MeldInput{{Tiles: []string{"B03-1", "B04-1", "B06-1"}}}},
+		{"tile used twice", hand, []MeldInput{run, {Tiles: []string{"B05-1", "R07-1", "B07-1"}}}},
+		{"invalid meld", hand, []MeldInput{{Tiles: []string{"B03-1", "B04-1", "K01-1"}}}},
+		{"nothing left to discard", []string{"B03-1", "B04-1", "B05-1"}, []MeldInput{run}},
+	}
+	for _, tc := range bad {
+		if _, _, _, err := validateOpening(tc.hand, tc.melds, "K10"); err == nil {
+			t.Errorf("%s: accepted", tc.name)
+		}
+	}
+}
+
+func mustMeld(t *testing.T, ids []string, want MeldType) TableMeld {
+	t.Helper()
+	m, err := buildTableMeld(ids, want, "K10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestLayoffMeld(t *testing.T) {
+	run := mustMeld(t, []string{"B03-1", "B04-1", "B05-1"}, MeldRun)
+	high := mustMeld(t, []string{"B11-1", "B12-1", "B13-1"}, MeldRun)
+	group := mustMeld(t, []string{"R07-1", "B07-1", "G07-1"}, MeldGroup)
+	full := mustMeld(t, []string{"R07-1", "B07-1", "G07-1", "K07-1"}, MeldGroup)
+	pair := mustMeld(t, []string{"B09-1", "B09-2"}, MeldPair)
+
+	tests := []struct {
+		name   string
+		m      TableMeld
+		tile   string
+		side   LayoffSide
+		ok     bool
+		points int
+	}{
+		{"run start", run, "B02-1", SideStart, true, 14},
+		{"run end", run, "B06-1", SideEnd, true, 18},
+		{"run end auto side", run, "B06-1", "", true, 18},
+		{"run wrong side", run, "B06-1", SideStart, false, 0},
+		{"run not adjacent", run, "B07-2", "", false, 0},
+		{"run other color", run, "R06-1", SideEnd, false, 0},
+		{"run okey end", run, "K10-1", SideEnd, true, 18},
+		{"run okey start", run, "K10-1", SideStart, true, 14},
+		{"run at 13 end", high, "B01-1", SideEnd, false, 0},
+		{"run okey at 13 end", high, "K10-1", SideEnd, false, 0},
+		{"run at 13 okey auto side", high, "K10-1", "", true, 46},
+		{"group missing color", group, "K07-1", "", true, 28},
+		{"group okey", group, "K10-2", "", true, 28},
+		{"group same color", group, "R07-2", "", false, 0},
+		{"group other number", group, "K08-1", "", false, 0},
+		{"group full", full, "R07-2", "", false, 0},
+		{"pair", pair, "K10-1", "", false, 0},
+	}
+	for _, tc := range tests {
+		out, err := layoffMeld(tc.m, tc.tile, tc.side, "K10")
+		if (err == nil) != tc.ok {
+			t.Errorf("%s: err = %v, want ok=%v", tc.name, err, tc.ok)
+			continue
+		}
+		if tc.ok && out.Points != tc.points {
+			t.Errorf("%s: points %d, want %d", tc.name, out.Points, tc.points)
+		}
+	}
+}
+
+func TestSwapOkeyInMeld(t *testing.T) {
+	run := mustMeld(t, []string{"B03-1", "K10-1", "B05-1"}, MeldRun)
+	group := mustMeld(t, []string{"R07-1", "B07-1", "K10-2"}, MeldGroup)
+	pair := mustMeld(t, []string{"B09-1", "K10-1"}, MeldPair)
+	plain := mustMeld(t, []string{"B03-1", "B04-1", "B05-1"}, MeldRun)
+
+	tests := []struct {
+		name string
+		m    TableMeld
+		tile string
+		okey string // alınan okey ("" = hata)
+	}{
+		{"run", run, "B04-2", "K10-1"},
+		{"run wrong number", run, "B06-1", ""},
+		{"run wrong color", run, "R04-1", ""},
+		{"group missing color", group, "G07-1", "K10-2"},
+		{"group color present", group, "R07-2", ""},
+		{"pair", pair, "B09-2", "K10-1"},
+		{"pair other color", pair, "R09-1", ""},
+		{"no okey in meld", plain, "B04-2", ""},
+		{"okey for okey", run, "K10-2", ""},
+	}
+	for _, tc := range tests {
+		out, got, err := swapOkeyInMeld(tc.m, tc.tile, "K10")
+		if tc.okey == "" {
+			if err == nil {
+				t.Errorf("%s: swap accepted", tc.name)
+			}
+			continue
+		}
+		if err != nil || got != tc.okey {
+			t.Errorf("%s: okey %q err %v, want %q", tc.name, got, err, tc.okey)
+			continue
+		}
+		for _, id := range out.Tiles {
+			if id == tc.okey {
+				t.Errorf("%s: okey still in meld %v", tc.name, out.Tiles)
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import "testing"
+
+// newTurnRoom: 2 kişilik masa, sıra 2. oyuncuda (WAIT_DRAW), solunda B07 var
+func newTurnRoom(t *testing.T) *Room {
+	t.Helper()
+	r, err := NewRoomManager().CreateRoom("u1", RoomConfig{Seats: 2, PenaltyMode: PenaltyOff})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Players[2] = &Player{UserID: "u2", Seat: 2, Connected: true}
+	r.resetTableLocked()
+	r.State = "PLAYING"
+	r.OkeyTileID = "R05"
+	r.TurnSeat = 2
+	r.TurnPhase = "WAIT_DRAW"
+	r.Hands[2] = []string{"K01-1", "G09-1", "R12-1"}
+	r.DrawPile = []string{"B01-1", "B02-1"}
+	r.DiscardStacks = map[int][]DiscardEvent{1: {{TileID: "B07-1", Seat: 1, UserID: "u1"}}}
+	r.Discards = []DiscardEvent{{TileID: "B07-1", Seat: 1, UserID: "u1"}}
+	return r
+}
+
+func TestUnusedTakeCannotBeRetaken(t *testing.T) {
+	r := newTurnRoom(t)
+	defer func() {
+		r.mu.Lock()
+		if r.turnTimer != nil {
+			r.turnTimer.Stop()
+		}
+		r.mu.Unlock()
+	}()
+
+	if err := r.drawDiscard("u2"); err != nil {
+		t.Fatalf("first take: %v", err)
+	}
+	r.mu.RLock()
+	gen := r.turnTimerGen
+	r.mu.RUnlock()
+
+	// alınan taş kullanılmadan başka taş atılırsa taş geri konur
+	if err := r.discard("u2", "K01-1"); err == nil {
+		t.Fatalf("discard with unused taken tile should fail")
+	}
+	r.mu.RLock()
+	phase, back, genAfter := r.TurnPhase, len(r.DiscardStacks[1]), r.turnTimerGen
+	r.mu.RUnlock()
+	if phase != "WAIT_DRAW" || back != 1 {
+		t.Fatalf("phase %q, left stack %d; want WAIT_DRAW and 1", phase, back)
+	}
+	if genAfter != gen {
+		t.Fatalf("turn timer reset on revert")
+	}
+
+	if err := r.drawDiscard("u2"); err == nil {
+		t.Fatalf("returned tile taken again in the same turn")
+	}
+	if err := r.draw("u2"); err != nil {
+		t.Fatalf("draw from pile: %v", err)
+	}
+}